@@ -0,0 +1,266 @@
+package syntaxhighlight
+
+import (
+	"bytes"
+	"regexp"
+	"strings"
+)
+
+// asmDialect is a dialect of assembly language.
+type asmDialect int
+
+const (
+	asmGo   asmDialect = iota // the Plan 9 style assembly of the Go toolchain
+	asmGAS                    // the GNU assembler, in AT&T syntax
+	asmNASM                   // the Netwide Assembler, in Intel syntax
+)
+
+// asmLexer tokenizes assembly language. Instructions are highlighted as
+// Keyword, directives (such as TEXT and GLOBL, .globl or section) as
+// Preprocessor, registers as Builtin and immediates and other numbers as
+// Decimal. Labels and the symbols of functions, such as ·foo(SB), are
+// highlighted as Function, and in Go assembly, arguments addressed from the
+// frame pointer, such as x+8(FP), as Parameter.
+type asmLexer struct {
+	dialect asmDialect
+}
+
+func init() {
+	Register(LexerConfig{
+		Name:      "goasm",
+		Aliases:   []string{"go-asm", "plan9asm"},
+		Filenames: []string{"*.s"},
+	}, asmLexer{asmGo})
+	Register(LexerConfig{
+		Name:      "gas",
+		Aliases:   []string{"asm", "gnuasm", "att"},
+		Filenames: []string{"*.S", "*.gas"},
+		MimeTypes: []string{"text/x-gas"},
+	}, asmLexer{asmGAS})
+	Register(LexerConfig{
+		Name:      "nasm",
+		Aliases:   []string{"yasm"},
+		Filenames: []string{"*.asm", "*.nasm", "*.ASM"},
+		MimeTypes: []string{"text/x-nasm"},
+	}, asmLexer{asmNASM})
+}
+
+// asmLexState is the state of the assembly lexer.
+type asmLexState struct {
+	*lexState
+	statementStart bool // whether an instruction or directive may start at pos
+}
+
+var (
+	// goAsmRegister matches the registers of the architectures supported by
+	// Go, including its pseudo-registers SB, FP, SP and PC.
+	goAsmRegister = regexp.MustCompile(`^([ABCD][XLH]|[SD]IB?|BPB?|SPB?|SB|FP|PC|R\d+[BWL]?|[XYZKVF]\d+|ZR|RSP|LR|CTR|g)$`)
+
+	// x86Register matches the registers of x86 and x86-64.
+	x86Register = regexp.MustCompile(`^(?i:[re]?[abcd]x|[abcd][lh]|[re]?[sd]il?|[re]?[sb]pl?|r\d+[dwb]?|[xyz]mm\d+|k[0-7]|[c-gs]s|[re]?ip|cr\d|dr\d|st\d?)$`)
+
+	// asmPrefixes are the instruction prefixes, after which an instruction
+	// follows.
+	asmPrefixes = wordSet("rep", "repe", "repz", "repne", "repnz", "lock", "REP", "LOCK")
+)
+
+func (l asmLexer) Tokens(src []byte) ([]Token, error) {
+	s := &asmLexState{lexState: newLexState(src), statementStart: true}
+	for !s.eof() {
+		l.lex(s)
+	}
+	return s.toks, nil
+}
+
+func (l asmLexer) keywordTable() *keywordTable {
+	switch l.dialect {
+	case asmGo:
+		return goAsmKeywords
+	case asmNASM:
+		return nasmKeywords
+	}
+	return nil // the directives of GAS all start with a period
+}
+
+// lex lexes a single token.
+func (l asmLexer) lex(s *asmLexState) {
+	if s.lexWhitespace() {
+		if strings.Contains(s.toks[len(s.toks)-1].Text, "\n") {
+			s.statementStart = true
+		}
+		return
+	}
+	c := s.peek(0)
+	switch {
+	case s.hasPrefix("//") && l.dialect != asmNASM, c == ';' && l.dialect == asmNASM:
+		s.acceptLine()
+		s.emit(Comment)
+	case s.hasPrefix("/*") && l.dialect != asmNASM:
+		if s.acceptUntil("*/") {
+			s.pos += 2
+		}
+		s.emit(Comment)
+	case c == '#' && l.dialect != asmNASM:
+		if s.atLineStart() && isIdentStartByte(s.peek(1)) && l.dialect == asmGo || asmIsCPPDirective(s) {
+			s.pos++
+			s.acceptWhile(isIdentPart)
+			include := s.text() == "#include"
+			s.emit(Preprocessor)
+			s.statementStart = false
+			if include && s.lexWhitespace() && s.peek(0) == '<' {
+				for !s.eof() && s.peek(0) != '>' && s.peek(0) != '\n' {
+					s.pos++
+				}
+				s.acceptByte('>')
+				s.emit(String)
+			}
+			return
+		}
+		s.acceptLine()
+		s.emit(Comment)
+	case c == '%' && l.dialect == asmNASM && s.atLineStart():
+		s.pos++
+		s.acceptWhile(isIdentPart)
+		s.emit(Preprocessor)
+		s.statementStart = false
+	case c == '%' && l.dialect == asmNASM && isDigit(s.peek(1)):
+		// A parameter of a macro.
+		s.pos++
+		s.acceptWhile(func(r rune) bool { return '0' <= r && r <= '9' })
+		s.emit(Parameter)
+	case c == '%' && isIdentStartByte(s.peek(1)):
+		s.pos++
+		s.acceptWhile(isIdentPart)
+		s.emit(Builtin)
+	case c == '"' || c == '\'' || c == '`' && l.dialect == asmNASM:
+		s.pos++
+		s.acceptQuoted(string(c), l.dialect != asmNASM || c == '`', false)
+		s.emit(String)
+	case c == '$' && (isDigit(s.peek(1)) || s.peek(1) == '-' && isDigit(s.peek(2))):
+		// An immediate, such as $0x10, or the frame and argument sizes of a
+		// function, such as $16-24.
+		s.pos++
+		s.acceptByte('-')
+		s.acceptNumber(0)
+		if l.dialect == asmGo && s.peek(0) == '-' && isDigit(s.peek(1)) {
+			s.pos++
+			s.acceptNumber(0)
+		}
+		s.emit(Decimal)
+	case s.acceptNumber(0):
+		s.acceptByte('h') // a NASM hexadecimal suffix
+		s.emit(Decimal)
+	case c == ';':
+		s.pos++
+		s.emit(Punctuation)
+		s.statementStart = true
+	case asmIsIdentStart(s):
+		l.lexIdent(s)
+	default:
+		_, w := s.peekRune()
+		s.pos += w
+		s.emit(Punctuation)
+	}
+}
+
+// asmIsCPPDirective reports whether a C preprocessor directive, as used in
+// .S files, starts at pos.
+func asmIsCPPDirective(s *asmLexState) bool {
+	if !s.atLineStart() {
+		return false
+	}
+	for _, d := range []string{"include", "define", "undef", "if", "ifdef", "ifndef", "elif", "else", "endif", "error"} {
+		if bytes.HasPrefix(s.src[s.pos+1:], []byte(d)) && !isIdentPart(rune(s.peek(1+len(d)))) {
+			return true
+		}
+	}
+	return false
+}
+
+// asmIsIdentStart reports whether a symbol starts at pos. Symbols may
+// contain the middle dot and division slash of Go assembly, which stand for
+// the periods and slashes of Go import paths.
+func asmIsIdentStart(s *asmLexState) bool {
+	r, _ := s.peekRune()
+	return isIdentStart(r) || r == '.' || r == '·'
+}
+
+func isAsmIdentPart(r rune) bool {
+	return isIdentPart(r) || r == '.' || r == '·' || r == '∕' || r == '$' || r == '@'
+}
+
+// lexIdent lexes an instruction, directive, label, register or symbol.
+func (l asmLexer) lexIdent(s *asmLexState) {
+	_, w := s.peekRune()
+	s.pos += w
+	s.acceptWhile(isAsmIdentPart)
+	if l.dialect == asmGo && s.hasPrefix("<>") {
+		s.pos += 2 // a symbol that is local to its file
+	}
+	text := s.text()
+
+	if s.statementStart && s.peek(0) == ':' {
+		s.emit(Function) // a label, after which a statement may follow
+		return
+	}
+	if s.statementStart {
+		s.statementStart = false
+		goKind, _ := goAsmKeywords.kind(text)
+		nasmKind, _ := nasmKeywords.kind(text)
+		_, prefix := asmPrefixes[text]
+		switch {
+		case l.dialect == asmGo && goKind == Keyword, l.dialect == asmNASM && nasmKind == Keyword, l.dialect != asmNASM && text[0] == '.':
+			s.emit(Preprocessor)
+		case l.dialect == asmNASM && asmNextWordIn(s, nasmKeywords.keywords):
+			s.emit(Function) // a label without a colon, as in "msg db 'hi'"
+			s.statementStart = true
+		default:
+			s.emit(Keyword)
+			s.statementStart = prefix
+		}
+		return
+	}
+
+	goKind, _ := goAsmKeywords.kind(text)
+	nasmKind, _ := nasmKeywords.kind(text)
+	switch {
+	case l.dialect == asmGo && goAsmRegister.MatchString(text), l.dialect == asmNASM && x86Register.MatchString(text):
+		s.emit(Builtin)
+	case l.dialect == asmGo && goKind == Literal:
+		s.emit(Constant)
+	case l.dialect == asmNASM && nasmKind == Type:
+		s.emit(Type)
+	case l.dialect == asmGo && asmIsFrameOffset(s, "FP"):
+		s.emit(Parameter)
+	case strings.ContainsRune(text, '·') || asmIsFrameOffset(s, "SB"):
+		s.emit(Function)
+	default:
+		s.emit(Plaintext)
+	}
+}
+
+// asmNextWordIn reports whether the word that follows pos is in words.
+func asmNextWordIn(s *asmLexState, words map[string]struct{}) bool {
+	i := s.pos
+	for i < len(s.src) && (s.src[i] == ' ' || s.src[i] == '\t') {
+		i++
+	}
+	j := i
+	for j < len(s.src) && isIdentPart(rune(s.src[j])) {
+		j++
+	}
+	_, ok := words[strings.ToLower(string(s.src[i:j]))]
+	return i > s.pos && ok
+}
+
+// asmIsFrameOffset reports whether the symbol before pos is addressed
+// relative to the given pseudo-register of Go assembly, as in ·foo(SB),
+// x+8(FP) or ·table+16(SB).
+func asmIsFrameOffset(s *asmLexState, register string) bool {
+	i := s.pos
+	if i < len(s.src) && (s.src[i] == '+' || s.src[i] == '-') {
+		for i++; i < len(s.src) && (isHexDigit(s.src[i]) || s.src[i] == 'x'); i++ {
+		}
+	}
+	return bytes.HasPrefix(s.src[i:], []byte("("+register+")"))
+}
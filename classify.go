@@ -0,0 +1,174 @@
+package syntaxhighlight
+
+import (
+	"math"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Classification is a language guessed by Classify, along with the
+// confidence (between 0 and 1) of the guess.
+type Classification struct {
+	Language   string
+	Confidence float64
+}
+
+// A keywordLexer is a lexer that looks up the identifiers of its language in
+// a keywordTable. Classify tells languages apart by the words of their
+// tables, so any such lexer that is registered takes part in it.
+type keywordLexer interface {
+	keywordTable() *keywordTable
+}
+
+// classifyMaxLen is the number of leading bytes of the source that Classify
+// looks at.
+const classifyMaxLen = 64 << 10
+
+// classifyMinWords is the number of words of its keyword table that the
+// source must contain for Classify to report a language at all.
+const classifyMinWords = 3
+
+// classifySmoothing is added to the count of every word in every table, so
+// that a word that is missing from a language's table makes the language
+// less likely rather than impossible.
+const classifySmoothing = 0.5
+
+// classifyBuiltinWeight is the count of a builtin in its table, relative to
+// keywords, types and literals: builtins, such as len or print, are more
+// often also ordinary names in other languages.
+const classifyBuiltinWeight = 0.5
+
+var (
+	// classifyWord matches the words that Classify looks up in the keyword
+	// tables, including the leading # of C preprocessor directives, the @
+	// of decorators, the period of Makefile special targets, the hyphens
+	// of Lisp symbols and the trailing ? or ! of Ruby and Lisp names.
+	classifyWord = regexp.MustCompile(`[#@.]?[A-Za-z_][A-Za-z0-9_]*(?:-[A-Za-z0-9_]+)*[?!]?`)
+
+	// classifyString matches double-quoted strings, whose words are prose
+	// rather than code.
+	classifyString = regexp.MustCompile(`"(?:[^"\\\n]|\\.)*"`)
+)
+
+// classifyLanguage is a registered language that Classify may guess.
+type classifyLanguage struct {
+	name  string
+	table *keywordTable
+	size  int // the number of words in table
+}
+
+// classifyLanguages returns the registered languages whose lexers have a
+// keyword table, skipping those whose table is that of a language registered
+// earlier (such as tsx, which shares the table of typescript).
+func classifyLanguages() []classifyLanguage {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	var langs []classifyLanguage
+	seen := make(map[*keywordTable]bool)
+	for _, r := range registry {
+		l, ok := r.lexer.(keywordLexer)
+		if !ok || r.lexer == FallbackLexer {
+			continue
+		}
+		t := l.keywordTable()
+		if t == nil || seen[t] {
+			continue
+		}
+		seen[t] = true
+		size := len(t.keywords) + len(t.types) + len(t.builtins) + len(t.literals)
+		langs = append(langs, classifyLanguage{name: r.config.Name, table: t, size: size})
+	}
+	return langs
+}
+
+// Classify guesses the language of src from its contents alone, for when the
+// file name gives no clue. It counts the words of src that are in the keyword
+// tables of the registered lexers, and scores each language by how likely
+// these words are to be drawn from its table (a naive Bayes classifier,
+// which favors the languages with the most words of src, and of those the
+// ones with the smallest tables). It returns the languages ranked by
+// confidence (highest first), or nil if src has too few such words.
+// Languages whose lexers have no keyword table, such as Markdown, are never
+// guessed.
+func Classify(src []byte) []Classification {
+	if len(src) > classifyMaxLen {
+		src = src[:classifyMaxLen]
+	}
+	langs := classifyLanguages()
+
+	// Count the words of src that are in any table.
+	vocab := make(map[string]bool)
+	for _, lang := range langs {
+		for _, set := range []map[string]struct{}{lang.table.keywords, lang.table.types, lang.table.builtins, lang.table.literals} {
+			for w := range set {
+				vocab[w] = true
+			}
+		}
+	}
+	counts := make(map[string]int)
+	for _, w := range classifyWord.FindAll(classifyString.ReplaceAll(src, []byte(`""`)), -1) {
+		if vocab[string(w)] || vocab[strings.ToLower(string(w))] {
+			counts[string(w)]++
+		}
+	}
+
+	// Score each language by the log-likelihood of the words.
+	v := float64(len(vocab))
+	scores := make([]float64, len(langs))
+	hits := make([]int, len(langs))
+	best, mostHits := math.Inf(-1), 0
+	for i, lang := range langs {
+		for w, n := range counts {
+			var count float64
+			if kind, ok := lang.table.kind(w); ok {
+				count = 1
+				if kind == Builtin {
+					count = classifyBuiltinWeight
+				}
+				hits[i] += n
+			}
+			scores[i] += float64(n) * math.Log((count+classifySmoothing)/(float64(lang.size)+classifySmoothing*v))
+		}
+		if hits[i] > 0 {
+			best = math.Max(best, scores[i])
+		}
+		if hits[i] > mostHits {
+			mostHits = hits[i]
+		}
+	}
+	if mostHits < classifyMinWords {
+		return nil
+	}
+
+	// The confidence of a language is its likelihood relative to the sum of
+	// those of the languages with any of the words, as if they were all
+	// equally likely a priori.
+	var sum float64
+	for i := range scores {
+		if hits[i] > 0 {
+			scores[i] = math.Exp(scores[i] - best)
+			sum += scores[i]
+		}
+	}
+	var cs []Classification
+	for i, lang := range langs {
+		if hits[i] > 0 {
+			cs = append(cs, Classification{Language: lang.name, Confidence: scores[i] / sum})
+		}
+	}
+	sort.Sort(byConfidence(cs))
+	return cs
+}
+
+type byConfidence []Classification
+
+func (cs byConfidence) Len() int      { return len(cs) }
+func (cs byConfidence) Swap(i, j int) { cs[i], cs[j] = cs[j], cs[i] }
+func (cs byConfidence) Less(i, j int) bool {
+	if cs[i].Confidence != cs[j].Confidence {
+		return cs[i].Confidence > cs[j].Confidence
+	}
+	return cs[i].Language < cs[j].Language
+}
@@ -0,0 +1,61 @@
+package syntaxhighlight
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+)
+
+// classifyMinAccuracy is the share of the samples in testdata/classify that
+// Classify must label correctly. Run "go test -run=TestClassifyCorpus -v" to
+// see the accuracy and the misclassified samples.
+const classifyMinAccuracy = 0.9
+
+func TestClassifyCorpus(t *testing.T) {
+	paths, err := filepath.Glob("testdata/classify/*/*")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(paths) == 0 {
+		t.Fatal("no samples in testdata/classify")
+	}
+
+	correct := 0
+	for _, path := range paths {
+		src, err := ioutil.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := filepath.Base(filepath.Dir(path))
+		cs := Classify(src)
+		if len(cs) > 0 && cs[0].Language == want {
+			correct++
+			continue
+		}
+		t.Logf("%s: got %+v, want %s", path, cs, want)
+	}
+
+	accuracy := float64(correct) / float64(len(paths))
+	t.Logf("accuracy: %d/%d (%.0f%%)", correct, len(paths), 100*accuracy)
+	if accuracy < classifyMinAccuracy {
+		t.Errorf("accuracy %.2f is below %.2f", accuracy, classifyMinAccuracy)
+	}
+}
+
+func TestClassify(t *testing.T) {
+	if cs := Classify([]byte("hello, world")); cs != nil {
+		t.Errorf("prose: got %+v, want no classification", cs)
+	}
+
+	cs := Classify([]byte("package main\n\nfunc main() {\n\tx := <-ch\n\tdefer close(ch)\n}\n"))
+	if len(cs) == 0 || cs[0].Language != "go" {
+		t.Fatalf("got %+v, want go first", cs)
+	}
+	var sum float64
+	for _, c := range cs {
+		sum += c.Confidence
+	}
+	if sum < 0.999 || sum > 1.001 {
+		t.Errorf("confidences sum to %f, want 1", sum)
+	}
+}
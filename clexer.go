@@ -0,0 +1,249 @@
+package syntaxhighlight
+
+import (
+	"bytes"
+	"strings"
+)
+
+// cLexer tokenizes C or, if cpp is set, C++ source code. Preprocessor
+// directives are highlighted as such, along with the header names and macro
+// names in them.
+type cLexer struct {
+	cpp bool
+
+	// directive is set when lexing the rest of a preprocessor directive,
+	// where "#" and "##" are operators rather than the start of another
+	// directive.
+	directive bool
+}
+
+func init() {
+	Register(LexerConfig{
+		Name:      "c",
+		Filenames: []string{"*.c", "*.h"},
+		MimeTypes: []string{"text/x-csrc", "text/x-chdr"},
+	}, cLexer{})
+	Register(LexerConfig{
+		Name:      "cpp",
+		Aliases:   []string{"c++", "cxx"},
+		Filenames: []string{"*.cpp", "*.cc", "*.cxx", "*.c++", "*.hpp", "*.hh", "*.hxx", "*.h++", "*.ipp", "*.tpp", "*.ino"},
+		MimeTypes: []string{"text/x-c++src", "text/x-c++hdr"},
+	}, cLexer{cpp: true})
+}
+
+var cOperators = []string{
+	"<<=", ">>=", "<=>", "->*", "...", "::", "->", ".*", "++", "--", "<<",
+	">>", "<=", ">=", "==", "!=", "&&", "||", "+=", "-=", "*=", "/=", "%=",
+	"&=", "|=", "^=",
+}
+
+func (l cLexer) Tokens(src []byte) ([]Token, error) {
+	s := newLexState(src)
+	for !s.eof() {
+		l.lex(s)
+	}
+	return s.toks, nil
+}
+
+func (l cLexer) keywordTable() *keywordTable {
+	if l.cpp {
+		return cppKeywords
+	}
+	return cKeywords
+}
+
+// lex lexes a single token, or a whole preprocessor directive.
+func (l cLexer) lex(s *lexState) {
+	if s.lexWhitespace() {
+		return
+	}
+	c := s.peek(0)
+	switch {
+	case s.hasPrefix("//"):
+		s.acceptLine()
+		s.emit(Comment)
+	case s.hasPrefix("/*"):
+		if s.acceptUntil("*/") {
+			s.pos += 2
+		}
+		s.emit(Comment)
+	case c == '#' && l.directive:
+		// The stringizing and token pasting operators.
+		s.pos++
+		s.acceptByte('#')
+		s.emit(Preprocessor)
+	case c == '#' && s.atLineStart():
+		l.lexDirective(s)
+	case c == '"' || c == '\'':
+		l.lexString(s, "")
+	case s.acceptNumber('\''):
+		s.emit(Decimal)
+	case isIdentStartByte(c) || c >= 0x80:
+		r, w := s.peekRune()
+		if !isIdentStart(r) {
+			s.pos += w
+			s.emit(Punctuation)
+			return
+		}
+		s.acceptWhile(isIdentPart)
+		if q := s.peek(0); (q == '"' || q == '\'') && l.isStringPrefix(s.text()) {
+			l.lexString(s, s.text())
+			return
+		}
+		s.emit(l.identKind(s))
+	case s.acceptAny(cOperators):
+		s.emit(Punctuation)
+	default:
+		_, w := s.peekRune()
+		s.pos += w
+		s.emit(Punctuation)
+	}
+}
+
+// isStringPrefix reports whether prefix is an encoding prefix of string and
+// character literals, such as "L" or "u8", or (in C++) one that makes a raw
+// string literal, such as "R" or "u8R".
+func (l cLexer) isStringPrefix(prefix string) bool {
+	if l.cpp && strings.HasSuffix(prefix, "R") {
+		prefix = prefix[:len(prefix)-1]
+		if prefix == "" {
+			return true
+		}
+	}
+	switch prefix {
+	case "L", "u", "U", "u8":
+		return true
+	}
+	return false
+}
+
+// lexString lexes a string or character literal, whose prefix has already
+// been consumed. The quote is at pos.
+func (l cLexer) lexString(s *lexState, prefix string) {
+	quote := s.peek(0)
+	s.pos++
+	if quote == '"' && strings.HasSuffix(prefix, "R") {
+		// A raw string, R"delim(...)delim".
+		i := bytes.IndexByte(s.src[s.pos:], '(')
+		if i >= 0 && i <= 16 && bytes.IndexAny(s.src[s.pos:s.pos+i], " \t\n\\)\"") < 0 {
+			end := ")" + string(s.src[s.pos:s.pos+i]) + `"`
+			s.pos += i + 1
+			if s.acceptUntil(end) {
+				s.pos += len(end)
+			}
+			l.acceptLiteralSuffix(s)
+			s.emit(String)
+			return
+		}
+	}
+	s.acceptQuoted(string(quote), true, false)
+	l.acceptLiteralSuffix(s)
+	s.emit(String)
+}
+
+// acceptLiteralSuffix advances past the suffix of a C++ user-defined string
+// literal, such as "s" in "str"s.
+func (l cLexer) acceptLiteralSuffix(s *lexState) {
+	if l.cpp && isIdentStartByte(s.peek(0)) {
+		s.acceptWhile(isIdentPart)
+	}
+}
+
+// lexDirective lexes a preprocessor directive, such as #include <stdio.h>.
+// The "#" is at pos.
+func (l cLexer) lexDirective(s *lexState) {
+	end := cDirectiveEnd(s.src, s.pos)
+	isBlank := func(r rune) bool { return r == ' ' || r == '\t' }
+	lexBlank := func() {
+		s.acceptWhile(isBlank)
+		s.emit(Whitespace)
+	}
+
+	s.pos++
+	s.acceptWhile(isBlank)
+	s.acceptWhile(isIdentPart)
+	name := strings.TrimLeft(s.text()[1:], " \t")
+	s.emit(Preprocessor)
+
+	switch name {
+	case "include", "include_next", "import":
+		lexBlank()
+		if s.peek(0) == '<' {
+			if i := bytes.IndexByte(s.src[s.pos:end], '>'); i >= 0 {
+				s.pos += i + 1
+				s.emit(String)
+			}
+		}
+	case "define", "undef", "ifdef", "ifndef":
+		lexBlank()
+		s.acceptWhile(isIdentPart)
+		s.emit(Constant)
+	case "error", "warning":
+		lexBlank()
+		s.pos = end
+		s.emit(String)
+	}
+
+	// The rest of the directive is code (or, in #define, the macro body).
+	s.pos = end
+	s.delegate(cLexer{cpp: l.cpp, directive: true}, Plaintext)
+}
+
+// cDirectiveEnd returns the offset of the newline that ends the preprocessor
+// directive starting at pos, or the length of src if there is none. Escaped
+// newlines continue the directive, as do newlines in block comments.
+func cDirectiveEnd(src []byte, pos int) int {
+	for i := pos; i < len(src); i++ {
+		switch c := src[i]; c {
+		case '\n':
+			return i
+		case '\\':
+			if bytes.HasPrefix(src[i+1:], []byte("\r\n")) {
+				i++
+			}
+			i++
+		case '/':
+			if bytes.HasPrefix(src[i:], []byte("/*")) {
+				j := bytes.Index(src[i+2:], []byte("*/"))
+				if j < 0 {
+					return len(src)
+				}
+				i += j + 3
+			}
+		case '"', '\'':
+			for i++; i < len(src) && src[i] != c && src[i] != '\n'; i++ {
+				if src[i] == '\\' {
+					i++
+				}
+			}
+			if i < len(src) && src[i] == '\n' {
+				return i
+			}
+		}
+	}
+	return len(src)
+}
+
+// identKind returns the kind of the identifier that is the current token.
+func (l cLexer) identKind(s *lexState) Kind {
+	ident := s.text()
+	if l.directive && (ident == "defined" || strings.HasPrefix(ident, "__has_")) {
+		return Preprocessor
+	}
+	prev, _ := s.last()
+	if prev.Text == "." || prev.Text == "->" {
+		return identKindByCase(ident)
+	}
+	if kind, ok := l.keywordTable().kind(ident); ok {
+		return kind
+	}
+	if prev.Kind == Keyword {
+		switch prev.Text {
+		case "struct", "union", "enum", "class", "typename", "concept":
+			return Type
+		case "namespace":
+			return Package
+		}
+	}
+	return identKindByCase(ident)
+}
@@ -5,10 +5,17 @@ import (
 	"fmt"
 	"io/ioutil"
 	"log"
+	"path/filepath"
 
 	"github.com/sourcegraph/syntaxhighlight"
 )
 
+var (
+	lang     = flag.String("lang", "", "language of the input file (default: detected from its name and contents)")
+	semantic = flag.Bool("semantic", false, "type-check Go files to highlight identifiers by what they denote")
+	contents = flag.Bool("diff-contents", false, "highlight the contents of diff lines in the language of their files")
+)
+
 func main() {
 	flag.Parse()
 
@@ -23,7 +30,23 @@ func main() {
 		log.Fatal(err)
 	}
 
-	html, err := syntaxhighlight.AsHTML(input)
+	language := *lang
+	if language == "" {
+		language = syntaxhighlight.DetectLanguage(flag.Arg(0), input)
+	}
+
+	opt := syntaxhighlight.Language(language)
+	if *semantic && language == "go" {
+		opt = syntaxhighlight.UseLexer(syntaxhighlight.GoSemanticLexer{
+			Filename: flag.Arg(0),
+			Dir:      filepath.Dir(flag.Arg(0)),
+		})
+	}
+	if *contents && language == "diff" {
+		opt = syntaxhighlight.UseLexer(syntaxhighlight.DiffLexer{HighlightContents: true})
+	}
+
+	html, err := syntaxhighlight.AsHTML(input, opt)
 	if err != nil {
 		log.Fatal(err)
 	}
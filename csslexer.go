@@ -0,0 +1,379 @@
+package syntaxhighlight
+
+import "strings"
+
+// cssDialect is CSS or one of the languages that extend it.
+type cssDialect int
+
+const (
+	cssStandard cssDialect = iota
+	cssSCSS
+	cssLess
+)
+
+// cssLexer tokenizes CSS, SCSS or Less style sheets. In selectors, element
+// names are highlighted as HTMLTag, classes as Type, IDs as Constant and
+// pseudo-classes as Builtin. In declarations, property names are
+// highlighted as HTMLAttrName and variables as Parameter; in values, hex
+// colors are Literal and numbers, along with their units, Decimal.
+type cssLexer struct {
+	dialect cssDialect
+}
+
+func init() {
+	Register(LexerConfig{
+		Name:      "css",
+		Filenames: []string{"*.css"},
+		MimeTypes: []string{"text/css"},
+	}, cssLexer{cssStandard})
+	Register(LexerConfig{
+		Name:      "scss",
+		Filenames: []string{"*.scss"},
+		MimeTypes: []string{"text/x-scss"},
+	}, cssLexer{cssSCSS})
+	Register(LexerConfig{
+		Name:      "less",
+		Filenames: []string{"*.less"},
+		MimeTypes: []string{"text/x-less"},
+	}, cssLexer{cssLess})
+}
+
+// cssContext is the part of a style sheet that a token is in.
+type cssContext int
+
+const (
+	cssStatementStart cssContext = iota
+	cssSelector
+	cssProperty
+	cssValue
+	cssAtRule // the prelude of an at-rule, such as "screen" in @media screen
+)
+
+// cssLexState is the state of the CSS lexer.
+type cssLexState struct {
+	*lexState
+	context cssContext
+}
+
+func (l cssLexer) Tokens(src []byte) ([]Token, error) {
+	s := &cssLexState{lexState: newLexState(src)}
+	for !s.eof() {
+		l.lex(s)
+	}
+	return s.toks, nil
+}
+
+// lex lexes a single token.
+func (l cssLexer) lex(s *cssLexState) {
+	if s.lexWhitespace() {
+		return
+	}
+	c := s.peek(0)
+	switch {
+	case s.hasPrefix("/*"):
+		if s.acceptUntil("*/") {
+			s.pos += 2
+		}
+		s.emit(Comment)
+		return
+	case s.hasPrefix("//") && l.dialect != cssStandard:
+		s.acceptLine()
+		s.emit(Comment)
+		return
+	case c == '"' || c == '\'':
+		s.pos++
+		s.acceptQuoted(string(c), true, false)
+		s.emit(String)
+		return
+	case s.hasPrefix("#{") && l.dialect == cssSCSS:
+		// An interpolation, whose contents are a value.
+		s.pos += 2
+		s.emit(Punctuation)
+		s.acceptBalanced('{', '}', `"'`)
+		s.delegate(cssValueLexer{l.dialect}, Plaintext)
+		if s.acceptByte('}') {
+			s.emit(Punctuation)
+		}
+		return
+	case c == '{':
+		s.pos++
+		s.emit(Punctuation)
+		s.context = cssStatementStart
+		return
+	case c == '}' || c == ';':
+		s.pos++
+		s.emit(Punctuation)
+		s.context = cssStatementStart
+		return
+	}
+
+	if s.context == cssStatementStart {
+		s.context = l.statementContext(s)
+	}
+	switch s.context {
+	case cssSelector:
+		l.lexSelector(s)
+	case cssProperty:
+		if c == ':' {
+			s.pos++
+			s.emit(Punctuation)
+			s.context = cssValue
+			return
+		}
+		l.lexProperty(s)
+	default:
+		l.lexValue(s)
+	}
+}
+
+// statementContext returns the context of a statement that starts at pos: an
+// at-rule, a declaration or a rule's selector.
+func (l cssLexer) statementContext(s *cssLexState) cssContext {
+	switch c := s.peek(0); {
+	case c == '@' && !(l.dialect == cssLess && cssIsDeclaration(s.lexState)):
+		return cssAtRule
+	case c == '$' && l.dialect == cssSCSS:
+		return cssProperty
+	case cssIsDeclaration(s.lexState):
+		return cssProperty
+	}
+	return cssSelector
+}
+
+// cssIsDeclaration reports whether the statement at pos is a declaration,
+// such as "color: red", rather than the selector of a nested rule, such as
+// "a:hover { ... }". A declaration is a name followed by a colon, and ends
+// before a block could start.
+func cssIsDeclaration(s *lexState) bool {
+	i := s.pos
+	for i < len(s.src) && (isCSSNamePart(rune(s.src[i])) || strings.IndexByte("@$*", s.src[i]) >= 0) {
+		i++
+	}
+	for i < len(s.src) && (s.src[i] == ' ' || s.src[i] == '\t') {
+		i++
+	}
+	if i == s.pos || i == len(s.src) || s.src[i] != ':' {
+		return false
+	}
+
+	// Look for the end of the statement, skipping over strings, parentheses
+	// and interpolations.
+	depth := 0
+	for ; i < len(s.src); i++ {
+		switch c := s.src[i]; c {
+		case '"', '\'':
+			for i++; i < len(s.src) && s.src[i] != c && s.src[i] != '\n'; i++ {
+				if s.src[i] == '\\' {
+					i++
+				}
+			}
+		case '(':
+			depth++
+		case ')':
+			depth--
+		case '#':
+			if i+1 < len(s.src) && s.src[i+1] == '{' {
+				depth++
+				i++
+			}
+		case '{':
+			if depth == 0 {
+				return false
+			}
+		case '}':
+			if depth == 0 {
+				return true
+			}
+			depth--
+		case ';':
+			if depth == 0 {
+				return true
+			}
+		}
+	}
+	return true
+}
+
+// lexSelector lexes a token of a selector.
+func (l cssLexer) lexSelector(s *cssLexState) {
+	c := s.peek(0)
+	switch {
+	case (c == '.' || c == '%') && isCSSNameStart(s.peek(1)):
+		s.pos++
+		s.acceptWhile(isCSSNamePart)
+		s.emit(Type)
+	case c == '#' && isCSSNamePart(rune(s.peek(1))):
+		s.pos++
+		s.acceptWhile(isCSSNamePart)
+		s.emit(Constant)
+	case c == ':' && (isCSSNameStart(s.peek(1)) || s.peek(1) == ':'):
+		s.pos++
+		s.acceptByte(':')
+		s.acceptWhile(isCSSNamePart)
+		s.emit(Builtin)
+	case (c == '@' && l.dialect == cssLess || c == '$' && l.dialect == cssSCSS) && isCSSNameStart(s.peek(1)):
+		// A parameter of a mixin.
+		s.pos++
+		s.acceptWhile(isCSSNamePart)
+		s.emit(Parameter)
+	case c == '[':
+		// An attribute selector, such as [type="text"].
+		s.pos++
+		s.emit(Punctuation)
+		s.lexWhitespace()
+		s.acceptWhile(isCSSNamePart)
+		s.emit(HTMLAttrName)
+		for !s.eof() && s.peek(0) != ']' && s.peek(0) != '{' {
+			switch q, w := s.peekRune(); {
+			case q == '"' || q == '\'':
+				s.pos++
+				s.acceptQuoted(string(q), true, false)
+				s.emit(String)
+			case isCSSNamePart(q):
+				s.acceptWhile(isCSSNamePart)
+				s.emit(HTMLAttrValue)
+			case !s.lexWhitespace():
+				s.pos += w
+				s.emit(Punctuation)
+			}
+		}
+		if s.acceptByte(']') {
+			s.emit(Punctuation)
+		}
+	case s.acceptNumber(0):
+		// A keyframe selector, such as 50%.
+		s.acceptByte('%')
+		s.emit(Decimal)
+	case isCSSNameStartAt(s.lexState):
+		s.acceptWhile(isCSSNamePart)
+		s.emit(HTMLTag)
+	default:
+		_, w := s.peekRune()
+		s.pos += w
+		s.emit(Punctuation)
+	}
+}
+
+// lexProperty lexes the name of a property or variable in a declaration.
+func (l cssLexer) lexProperty(s *cssLexState) {
+	switch c := s.peek(0); {
+	case c == '$' || c == '@' || s.hasPrefix("--"):
+		s.pos++
+		s.acceptWhile(isCSSNamePart)
+		s.emit(Parameter)
+	case isCSSNameStart(c) || c == '*':
+		s.pos++ // "*" is an old IE hack
+		s.acceptWhile(isCSSNamePart)
+		s.emit(HTMLAttrName)
+	default:
+		_, w := s.peekRune()
+		s.pos += w
+		s.emit(Punctuation)
+	}
+}
+
+// lexValue lexes a token of a property value or an at-rule prelude.
+func (l cssLexer) lexValue(s *cssLexState) {
+	if s.context == cssAtRule && s.peek(0) == '@' && !cssAfterAtKeyword(s) {
+		s.pos++
+		s.acceptWhile(isCSSNamePart)
+		s.emit(Keyword)
+		return
+	}
+	lexCSSValue(s.lexState, l.dialect)
+}
+
+// cssAfterAtKeyword reports whether the current token follows the at-keyword
+// of the at-rule it is in.
+func cssAfterAtKeyword(s *cssLexState) bool {
+	prev, ok := s.last()
+	return ok && prev.Text != "{" && prev.Text != "}" && prev.Text != ";"
+}
+
+// lexCSSValue lexes a token of a value.
+func lexCSSValue(s *lexState, dialect cssDialect) {
+	c := s.peek(0)
+	switch {
+	case c == '"' || c == '\'':
+		s.pos++
+		s.acceptQuoted(string(c), true, false)
+		s.emit(String)
+	case c == '#' && isHexDigit(s.peek(1)):
+		s.pos++
+		s.acceptWhile(isCSSNamePart)
+		s.emit(Literal)
+	case c == '!' && isIdentStartByte(s.peek(1)):
+		s.pos++
+		s.acceptWhile(isCSSNamePart)
+		s.emit(Keyword)
+	case c == '$' && dialect == cssSCSS, c == '@' && dialect == cssLess, s.hasPrefix("--") && isCSSNameStart(s.peek(2)):
+		s.pos++
+		s.acceptWhile(isCSSNamePart)
+		s.emit(Parameter)
+	case c == '@' && isCSSNameStart(s.peek(1)):
+		s.pos++
+		s.acceptWhile(isCSSNamePart)
+		s.emit(Keyword)
+	case c == '-' && (isDigit(s.peek(1)) || s.peek(1) == '.' && isDigit(s.peek(2))):
+		s.pos++
+		s.acceptNumber(0)
+		s.acceptByte('%')
+		s.emit(Decimal)
+	case s.acceptNumber(0):
+		s.acceptByte('%')
+		s.emit(Decimal)
+	case isCSSNameStartAt(s):
+		s.acceptWhile(isCSSNamePart)
+		if s.peek(0) != '(' {
+			s.emit(Plaintext)
+			return
+		}
+		name := strings.ToLower(s.text())
+		s.emit(Function)
+		if name == "url" && s.peek(1) != '"' && s.peek(1) != '\'' {
+			// An unquoted URL, which may contain any character but ")".
+			s.pos++
+			s.emit(Punctuation)
+			for !s.eof() && s.peek(0) != ')' && s.peek(0) != '\n' {
+				s.pos++
+			}
+			s.emit(String)
+		}
+	default:
+		_, w := s.peekRune()
+		s.pos += w
+		s.emit(Punctuation)
+	}
+}
+
+// cssValueLexer tokenizes a value, such as the contents of an SCSS
+// interpolation.
+type cssValueLexer struct {
+	dialect cssDialect
+}
+
+func (l cssValueLexer) Tokens(src []byte) ([]Token, error) {
+	s := newLexState(src)
+	for !s.eof() {
+		if !s.lexWhitespace() {
+			lexCSSValue(s, l.dialect)
+		}
+	}
+	return s.toks, nil
+}
+
+func isCSSNameStart(c byte) bool {
+	return isIdentStartByte(c) || c == '-' || c >= 0x80
+}
+
+// isCSSNameStartAt reports whether an identifier starts at pos.
+func isCSSNameStartAt(s *lexState) bool {
+	r, _ := s.peekRune()
+	return isIdentStart(r) || r == '-'
+}
+
+// isCSSNamePart reports whether r may be part of an identifier, such as
+// "font-size" or "-webkit-box".
+func isCSSNamePart(r rune) bool {
+	return isIdentPart(r) || r == '-'
+}
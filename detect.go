@@ -0,0 +1,138 @@
+package syntaxhighlight
+
+import (
+	"bytes"
+	"path"
+	"regexp"
+	"strings"
+)
+
+// DetectLanguage returns the name of the registered language that src,
+// read from the file named filename, is most likely written in. It returns
+// "" if the language could not be determined; the result can be passed to
+// Language and Lookup either way.
+//
+// In order of precedence, it looks at vim and emacs modelines, the file name
+// (see LookupFilename), the interpreter named on a "#!" line and, as a last
+// resort, the most confident guess of Classify. Either of filename and src
+// may be empty.
+func DetectLanguage(filename string, src []byte) string {
+	if r := lookupModeline(src); r != nil {
+		return r.config.Name
+	}
+	if filename != "" {
+		if r := lookupFilename(filename); r != nil {
+			return r.config.Name
+		}
+	}
+	if r := lookupShebang(src); r != nil {
+		return r.config.Name
+	}
+	if cs := Classify(src); len(cs) > 0 && cs[0].Confidence >= detectMinConfidence {
+		return cs[0].Language
+	}
+	return ""
+}
+
+// detectMinConfidence is the confidence a guess of Classify needs to be
+// returned by DetectLanguage.
+const detectMinConfidence = 0.6
+
+// LookupInterpreter returns the lexer registered for the given interpreter
+// (as named on a "#!" line), or nil if there is none.
+func LookupInterpreter(interpreter string) Lexer {
+	if r := lookupInterpreter(interpreter); r != nil {
+		return r.lexer
+	}
+	return nil
+}
+
+func lookupInterpreter(interpreter string) *registration {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	for _, name := range []string{interpreter, strings.TrimRight(interpreter, "0123456789.")} {
+		for _, r := range registry {
+			for _, i := range r.config.Interpreters {
+				if i == name {
+					return r
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// lookupShebang finds the lexer for the interpreter named on the "#!" line
+// of src. Both "#!/usr/bin/python3" and "#!/usr/bin/env -S python3 -u" are
+// understood.
+func lookupShebang(src []byte) *registration {
+	if !bytes.HasPrefix(src, []byte("#!")) {
+		return nil
+	}
+	line := src[2:]
+	if i := bytes.IndexByte(line, '\n'); i >= 0 {
+		line = line[:i]
+	}
+	fields := strings.Fields(string(line))
+	if len(fields) == 0 {
+		return nil
+	}
+	interpreter := path.Base(fields[0])
+	if interpreter == "env" {
+		interpreter = ""
+		for _, f := range fields[1:] {
+			if !strings.HasPrefix(f, "-") && !strings.Contains(f, "=") {
+				interpreter = path.Base(f)
+				break
+			}
+		}
+	}
+	if interpreter == "" {
+		return nil
+	}
+	return lookupInterpreter(interpreter)
+}
+
+var (
+	// vimModeline matches "vim: set ft=go:" and "vi: filetype=go" style modelines.
+	vimModeline = regexp.MustCompile(`(?:^|\s)(?:vi|vim|Vim|ex):.*?\b(?:ft|filetype|syntax)=([\w+#-]+)`)
+
+	// emacsModeline matches "-*- mode: go -*-" and "-*- go -*-" style modelines.
+	emacsModeline = regexp.MustCompile(`-\*-\s*(?:.*?\bmode:\s*([\w+#-]+)|([\w+#-]+))\s*;?.*?-\*-`)
+)
+
+// modelineLines is the number of lines at the start and at the end of a file
+// that are searched for modelines, matching vim's default 'modelines'.
+const modelineLines = 5
+
+// lookupModeline finds the lexer for the language named in a vim or emacs
+// modeline near the start or the end of src.
+func lookupModeline(src []byte) *registration {
+	lines := bytes.Split(src, []byte("\n"))
+	var candidates [][]byte
+	if len(lines) <= 2*modelineLines {
+		candidates = lines
+	} else {
+		candidates = append(candidates, lines[:modelineLines]...)
+		candidates = append(candidates, lines[len(lines)-modelineLines:]...)
+	}
+
+	for _, line := range candidates {
+		if m := emacsModeline.FindSubmatch(line); m != nil {
+			name := m[1]
+			if name == nil {
+				name = m[2]
+			}
+			if r := lookupName(string(name)); r != nil {
+				return r
+			}
+		}
+		if m := vimModeline.FindSubmatch(line); m != nil {
+			if r := lookupName(string(m[1])); r != nil {
+				return r
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,36 @@
+package syntaxhighlight
+
+import "testing"
+
+func TestDetectLanguage(t *testing.T) {
+	tests := []struct {
+		filename string
+		src      string
+		want     string
+	}{
+		{"main.go", "package main", "go"},
+		{"src/Makefile", "all:\n", "makefile"},
+		{"Dockerfile.dev", "FROM scratch\n", "dockerfile"},
+		{"go.mod", "module example.com/m\n", "gomod"},
+		{"work/go.work.sum", "", "gosum"},
+		{"script", "#!/usr/bin/python3\nprint(1)\n", "python"},
+		{"script", "#!/usr/bin/env -S ruby -w\nputs 1\n", "ruby"},
+		{"script", "#!/usr/bin/env node\n", "javascript"},
+		{"bin/console", "#!/usr/bin/env php\n<?php\n", "php"},
+		{"install", "#!/bin/sh\nset -e\n", "bash"},
+		{"script", "#!/bin/unknown\n", ""},
+		{"foo.txt", "# vim: set ft=python:\n", "python"},
+		{"foo.txt", "/* vi: ts=4 filetype=c */\n", "c"},
+		{"foo.h", "// -*- mode: go; tab-width: 4 -*-\n", "go"},
+		{"foo", "# -*- ruby -*-\n", "ruby"},
+		{"foo", "# -*- coding: utf-8 -*-\n", ""},
+		{"foo.txt", "a\nb\nc\nd\ne\nf\ng\nh\ni\nj\nk\n// vim: ft=go\n", "go"},
+		{"paste", "package main\n\nfunc main() {\n\tdefer f()\n\tx := 1\n}\n", "go"},
+		{"", "", ""},
+	}
+	for _, test := range tests {
+		if got := DetectLanguage(test.filename, []byte(test.src)); got != test.want {
+			t.Errorf("DetectLanguage(%q, %q): got %q, want %q", test.filename, test.src, got, test.want)
+		}
+	}
+}
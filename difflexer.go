@@ -0,0 +1,275 @@
+package syntaxhighlight
+
+import (
+	"bytes"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// DiffLexer is a lexer for unified diffs, such as those made by diff -u and
+// git diff. File headers are highlighted as Keyword, hunk headers as
+// Preprocessor, and added and removed lines as Inserted and Deleted.
+//
+// If HighlightContents is set, the contents of the lines in hunks are
+// highlighted too, by the lexer for the file named in the "+++ b/path"
+// header, and only the "+" and "-" markers are Inserted and Deleted:
+//
+//	AsHTML(src, UseLexer(DiffLexer{HighlightContents: true}))
+//
+// The old and new sides of each hunk are tokenized as wholes, so that
+// constructs spanning lines, such as block comments, are highlighted right.
+type DiffLexer struct {
+	HighlightContents bool
+}
+
+func init() {
+	Register(LexerConfig{
+		Name:      "diff",
+		Aliases:   []string{"patch", "udiff"},
+		Filenames: []string{"*.diff", "*.patch"},
+		MimeTypes: []string{"text/x-diff", "text/x-patch"},
+	}, DiffLexer{})
+}
+
+// diffLexState is the state of the diff lexer.
+type diffLexState struct {
+	*lexState
+	lexer Lexer // the lexer for the contents of the current file, if any
+}
+
+// diffHunkHeader matches a hunk header, such as "@@ -1,3 +1,4 @@", whose
+// line counts default to 1.
+var diffHunkHeader = regexp.MustCompile(`^@@ -\d+(?:,(\d+))? \+\d+(?:,(\d+))? @@`)
+
+// diffExtendedHeaders are the prefixes of the lines of extended headers of
+// git diffs, which describe changes to files other than to their contents.
+var diffExtendedHeaders = []string{
+	"index ", "old mode ", "new mode ", "deleted file mode ", "new file mode ",
+	"similarity index ", "dissimilarity index ", "rename from ", "rename to ",
+	"copy from ", "copy to ", "Binary files ", "Only in ",
+}
+
+func (l DiffLexer) Tokens(src []byte) ([]Token, error) {
+	s := &diffLexState{lexState: newLexState(src)}
+	for !s.eof() {
+		l.lexLine(s)
+	}
+	return s.toks, nil
+}
+
+// lexLine lexes a line outside of hunks, or a hunk header and the lines of
+// its hunk.
+func (l DiffLexer) lexLine(s *diffLexState) {
+	switch {
+	case s.hasPrefix("diff "):
+		s.acceptLine()
+		s.emit(Keyword)
+		s.lexer = nil
+	case s.hasPrefix("--- ") || s.hasPrefix("+++ "):
+		s.acceptLine()
+		if lexer := LookupFilename(diffPath(s.text())); lexer != nil || strings.HasPrefix(s.text(), "+++ ") {
+			s.lexer = lexer
+		}
+		s.emit(Keyword)
+	case s.hasPrefix("@@"):
+		m := diffHunkHeader.FindSubmatch(s.src[s.pos:])
+		if m == nil {
+			s.acceptLine()
+			s.emit(Preprocessor)
+			break
+		}
+		s.pos += len(m[0])
+		s.emit(Preprocessor)
+		// The rest of the line is the section heading, such as the
+		// signature of the enclosing function.
+		s.acceptLine()
+		if l.HighlightContents && s.lexer != nil {
+			s.delegate(s.lexer, Plaintext)
+		}
+		s.emit(Plaintext)
+		s.acceptByte('\n')
+		s.emit(Whitespace)
+		l.lexHunk(s, diffLineCount(m[1]), diffLineCount(m[2]))
+		return
+	case s.hasPrefix("+"):
+		s.acceptLine()
+		s.emit(Inserted)
+	case s.hasPrefix("-"):
+		s.acceptLine()
+		s.emit(Deleted)
+	case s.hasPrefix(`\`):
+		s.acceptLine()
+		s.emit(Comment)
+	case s.acceptAny(diffExtendedHeaders):
+		s.acceptLine()
+		s.emit(Comment)
+	default:
+		s.acceptLine()
+		s.emit(Plaintext)
+	}
+	s.acceptByte('\n')
+	s.emit(Whitespace)
+}
+
+// diffPath returns the path of the file named in a "---" or "+++" header,
+// without the "a/" or "b/" prefix of git diffs, or "" for /dev/null.
+func diffPath(header string) string {
+	path := header[len("+++ "):]
+	if i := strings.IndexByte(path, '\t'); i >= 0 {
+		path = path[:i] // a timestamp follows
+	}
+	path = strings.Trim(strings.TrimSpace(path), `"`)
+	if path == "/dev/null" {
+		return ""
+	}
+	if strings.HasPrefix(path, "a/") || strings.HasPrefix(path, "b/") {
+		path = path[2:]
+	}
+	return path
+}
+
+// diffLineCount parses the line count of a hunk header, which is 1 if
+// omitted.
+func diffLineCount(count []byte) int {
+	if count == nil {
+		return 1
+	}
+	n, _ := strconv.Atoi(string(count))
+	return n
+}
+
+// diffLine is a line of a hunk.
+type diffLine struct {
+	marker     byte // ' ', '+', '-' or '\\'
+	start, end int  // the offsets of the line's contents, between the marker and the newline
+}
+
+// lexHunk lexes the lines of a hunk, which has oldLines lines of the old
+// file and newLines of the new. The counts tell the lines of the hunk apart
+// from the headers that follow it, which they may look like.
+func (l DiffLexer) lexHunk(s *diffLexState, oldLines, newLines int) {
+	var lines []diffLine
+	for i := s.pos; i < len(s.src) && (oldLines > 0 || newLines > 0 || s.src[i] == '\\'); {
+		line := diffLine{marker: s.src[i], start: i + 1}
+		switch line.marker {
+		case ' ':
+			oldLines--
+			newLines--
+		case '\n':
+			// A context line whose space has been stripped.
+			line.marker, line.start = ' ', i
+			oldLines--
+			newLines--
+		case '-':
+			oldLines--
+		case '+':
+			newLines--
+		case '\\':
+		default:
+			oldLines, newLines = 0, 0 // a truncated hunk
+			continue
+		}
+		line.end = len(s.src)
+		if j := bytes.IndexByte(s.src[line.start:], '\n'); j >= 0 {
+			line.end = line.start + j
+		}
+		lines = append(lines, line)
+		i = line.end + 1
+	}
+
+	var oldToks, newToks map[int][]Token
+	if l.HighlightContents && s.lexer != nil {
+		oldToks = diffSideTokens(s.lexer, s.src, lines, '-', false)
+		newToks = diffSideTokens(s.lexer, s.src, lines, '+', true)
+	}
+	for i, line := range lines {
+		side := newToks
+		if line.marker == '-' {
+			side = oldToks
+		}
+		switch {
+		case line.marker == '\\':
+			s.pos = line.end
+			s.emit(Comment)
+		case side != nil:
+			s.pos = line.start
+			s.emit(diffMarkerKind(line.marker, Whitespace))
+			for _, tok := range side[i] {
+				s.pos = tok.Offset
+				s.emit(Plaintext)
+				s.pos += len(tok.Text)
+				s.emit(tok.Kind)
+			}
+			s.pos = line.end
+			s.emit(Plaintext)
+		default:
+			s.pos = line.end
+			s.emit(diffMarkerKind(line.marker, Plaintext))
+		}
+		s.acceptByte('\n')
+		s.emit(Whitespace)
+	}
+}
+
+// diffMarkerKind returns the kind of an added or removed line, or of its
+// marker, or context for a context line.
+func diffMarkerKind(marker byte, context Kind) Kind {
+	switch marker {
+	case '+':
+		return Inserted
+	case '-':
+		return Deleted
+	}
+	return context
+}
+
+// diffSideTokens tokenizes a side of a hunk with l: the context lines along
+// with either the removed lines (for marker '-') or the added lines (for
+// '+'), joined as they are in the old or new file. It returns the tokens of
+// the lines with the marker, and of the context lines if context is set, by
+// their index in lines. It returns nil if l fails.
+func diffSideTokens(l Lexer, src []byte, lines []diffLine, marker byte, context bool) map[int][]Token {
+	var text []byte
+	var starts, indexes []int // the offset in text and the index in lines of each line of the side
+	for i, line := range lines {
+		if line.marker == marker || line.marker == ' ' {
+			starts = append(starts, len(text))
+			indexes = append(indexes, i)
+			text = append(text, src[line.start:line.end]...)
+			text = append(text, '\n')
+		}
+	}
+	toks, err := l.Tokens(text)
+	if err != nil {
+		return nil
+	}
+
+	// Split the tokens into lines, and move them to the lines' places in
+	// src.
+	lineToks := make(map[int][]Token)
+	for _, tok := range toks {
+		for offset, rest := tok.Offset, tok.Text; rest != ""; {
+			piece := rest
+			if n := strings.IndexByte(rest, '\n'); n >= 0 {
+				piece = rest[:n]
+				rest = rest[n+1:]
+			} else {
+				rest = ""
+			}
+			if piece != "" {
+				j := sort.SearchInts(starts, offset+1) - 1
+				if line := lines[indexes[j]]; line.marker == marker || context {
+					lineToks[indexes[j]] = append(lineToks[indexes[j]], Token{
+						Kind:   tok.Kind,
+						Offset: line.start + offset - starts[j],
+						Text:   piece,
+					})
+				}
+			}
+			offset += len(piece) + 1
+		}
+	}
+	return lineToks
+}
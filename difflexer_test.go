@@ -0,0 +1,51 @@
+package syntaxhighlight
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDiffLexerHighlightContents(t *testing.T) {
+	src := "--- a/x.go\n+++ b/x.go\n@@ -1,2 +1,2 @@\n /* a\n-b */ x\n+c */ y\n"
+	want := []Token{
+		{Keyword, 0, "--- a/x.go"}, {Whitespace, 10, "\n"},
+		{Keyword, 11, "+++ b/x.go"}, {Whitespace, 21, "\n"},
+		{Preprocessor, 22, "@@ -1,2 +1,2 @@"}, {Whitespace, 37, "\n"},
+		{Whitespace, 38, " "}, {Comment, 39, "/* a"}, {Whitespace, 43, "\n"},
+		{Deleted, 44, "-"}, {Comment, 45, "b */"}, {Whitespace, 49, " "}, {Plaintext, 50, "x"}, {Whitespace, 51, "\n"},
+		{Inserted, 52, "+"}, {Comment, 53, "c */"}, {Whitespace, 57, " "}, {Plaintext, 58, "y"}, {Whitespace, 59, "\n"},
+	}
+	got, err := DiffLexer{HighlightContents: true}.Tokens([]byte(src))
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkTokens(t, "diff", []byte(src), got)
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got  %v\nwant %v", got, want)
+	}
+}
+
+func TestDiffLexerFileWithoutLexer(t *testing.T) {
+	// The second file has no lexer, so its contents aren't highlighted as Go.
+	src := "--- a/x.go\n+++ b/x.go\n@@ -1 +1 @@\n-func\n+func\n" +
+		"--- a/y.unknownext\n+++ b/y.unknownext\n@@ -1 +1 @@\n-func\n+func\n"
+	got, err := DiffLexer{HighlightContents: true}.Tokens([]byte(src))
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkTokens(t, "diff", []byte(src), got)
+	second := len(src) - len("-func\n+func\n")
+	var rest []Token
+	for _, tok := range got {
+		if tok.Offset >= second {
+			rest = append(rest, tok)
+		}
+	}
+	want := []Token{
+		{Deleted, second, "-func"}, {Whitespace, second + 5, "\n"},
+		{Inserted, second + 6, "+func"}, {Whitespace, second + 11, "\n"},
+	}
+	if !reflect.DeepEqual(rest, want) {
+		t.Errorf("got  %v\nwant %v", rest, want)
+	}
+}
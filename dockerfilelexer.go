@@ -0,0 +1,297 @@
+package syntaxhighlight
+
+import (
+	"bytes"
+	"regexp"
+	"strings"
+)
+
+// dockerfileLexer tokenizes Dockerfiles. Instructions are highlighted as
+// Keyword and parser directives as Preprocessor. The commands of RUN, CMD
+// and ENTRYPOINT are tokenized by the shell lexer, or by the JSON lexer in
+// their exec form; the variables set by ARG and ENV, and their expansions,
+// are highlighted as Parameter.
+type dockerfileLexer struct{}
+
+func init() {
+	Register(LexerConfig{
+		Name:      "dockerfile",
+		Aliases:   []string{"docker"},
+		Filenames: []string{"Dockerfile", "Containerfile", "Dockerfile.*", "*.dockerfile"},
+		MimeTypes: []string{"text/x-dockerfile"},
+	}, dockerfileLexer{})
+}
+
+var (
+	// dockerfileDirective matches a parser directive, such as
+	// "# syntax=docker/dockerfile:1".
+	dockerfileDirective = regexp.MustCompile(`^#\s*(syntax|escape|check)\s*=\s*(\S*)`)
+
+	// dockerfileHeredoc matches the opening of a heredoc, such as <<EOF or
+	// <<-"EOF".
+	dockerfileHeredoc = regexp.MustCompile(`<<(-?)(["']?)([A-Za-z_][A-Za-z0-9_]*)(["']?)`)
+)
+
+// dockerfileLexState is the state of the Dockerfile lexer.
+type dockerfileLexState struct {
+	*lexState
+	escape byte // the escape character, which continues instructions onto the next line
+}
+
+func (dockerfileLexer) Tokens(src []byte) ([]Token, error) {
+	s := &dockerfileLexState{lexState: newLexState(src), escape: '\\'}
+	directives := true // whether parser directives may follow
+	for !s.eof() {
+		if s.lexWhitespace() {
+			continue
+		}
+		if s.peek(0) == '#' {
+			s.acceptLine()
+			if m := dockerfileDirective.FindStringSubmatch(s.text()); m != nil && directives {
+				if m[1] == "escape" && len(m[2]) == 1 {
+					s.escape = m[2][0]
+				}
+				s.emit(Preprocessor)
+			} else {
+				s.emit(Comment)
+			}
+			continue
+		}
+		directives = false
+		lexDockerfileInstruction(s)
+	}
+	return s.toks, nil
+}
+
+func (dockerfileLexer) keywordTable() *keywordTable { return dockerfileKeywords }
+
+// lexDockerfileInstruction lexes an instruction and its arguments, which end
+// at the end of the line unless it ends with the escape character, or at the
+// end of the last of its heredocs.
+func lexDockerfileInstruction(s *dockerfileLexState) {
+	s.acceptWhile(isIdentPart)
+	instr := strings.ToUpper(s.text())
+	if _, ok := dockerfileKeywords.kind(instr); !ok {
+		s.acceptLine()
+		s.emit(Plaintext)
+		return
+	}
+	s.emit(Keyword)
+	end := s.instructionEnd()
+	lexDockerfileFlags(s, end)
+
+	switch instr {
+	case "ONBUILD":
+		lexDockerfileInstruction(s)
+		return
+	case "HEALTHCHECK":
+		if s.pos < end {
+			lexDockerfileInstruction(s) // CMD or NONE
+		}
+		return
+	}
+
+	base := s.pos
+	heredocs := dockerfileHeredoc.FindAllSubmatchIndex(s.src[base:end], -1)
+	switch {
+	case s.peek(0) == '[':
+		// The exec form of a command, which is a JSON array.
+		s.pos = end
+		s.delegate(jsonLexer{}, Plaintext)
+	case instr == "RUN" && len(heredocs) > 0 && heredocs[0][0] > 0:
+		// A command that reads heredocs, which the shell lexer understands.
+		s.pos = dockerfileHeredocsEnd(s.src, base, end, heredocs)
+		s.delegate(shellLexer{}, Plaintext)
+	case len(heredocs) > 0:
+		// A script to run, as in RUN <<EOF, or files to copy.
+		lexDockerfileWords(s, instr, end)
+		lexDockerfileHeredocBodies(s, instr, base, heredocs)
+	case instr == "RUN" || instr == "CMD" || instr == "ENTRYPOINT":
+		s.pos = end
+		s.delegate(shellLexer{}, Plaintext)
+	default:
+		lexDockerfileWords(s, instr, end)
+	}
+}
+
+// instructionEnd returns the end of the instruction at pos: the end of the
+// first line that doesn't end with the escape character.
+func (s *dockerfileLexState) instructionEnd() int {
+	for i := s.pos; ; {
+		nl := bytes.IndexByte(s.src[i:], '\n')
+		if nl < 0 {
+			return len(s.src)
+		}
+		line := bytes.TrimRight(s.src[i:i+nl], " \t\r")
+		if len(line) == 0 || line[len(line)-1] != s.escape {
+			if i > s.pos && bytes.HasPrefix(bytes.TrimLeft(line, " \t"), []byte("#")) {
+				// A comment line within the instruction.
+				i += nl + 1
+				continue
+			}
+			return i + nl
+		}
+		i += nl + 1
+	}
+}
+
+// dockerfileHeredocsEnd returns the end of the line that terminates the
+// last of the heredocs opened in src[base:end], whose offsets in heredocs
+// are relative to base.
+func dockerfileHeredocsEnd(src []byte, base, end int, heredocs [][]int) int {
+	for _, h := range heredocs {
+		_, end = dockerfileHeredocBody(src, end, base, h)
+	}
+	return end
+}
+
+// dockerfileHeredocBody returns the start of the line that terminates the
+// heredoc h, whose body starts after the newline at nl, and the end of that
+// line. The offsets in h are relative to base.
+func dockerfileHeredocBody(src []byte, nl, base int, h []int) (int, int) {
+	stripTabs := h[3] > h[2]
+	delim := src[base+h[6] : base+h[7]]
+	for i := nl + 1; i < len(src); {
+		lineEnd := bytes.IndexByte(src[i:], '\n')
+		if lineEnd < 0 {
+			lineEnd = len(src)
+		} else {
+			lineEnd += i
+		}
+		line := bytes.TrimRight(src[i:lineEnd], "\r")
+		if stripTabs {
+			line = bytes.TrimLeft(line, "\t")
+		}
+		if bytes.Equal(line, delim) {
+			return i, lineEnd
+		}
+		i = lineEnd + 1
+	}
+	return len(src), len(src)
+}
+
+// lexDockerfileHeredocBodies lexes the bodies and terminators of the
+// heredocs opened on the line that ends at pos. The body of the script of a
+// RUN instruction is tokenized by the lexer for the interpreter named on its
+// "#!" line, or by the shell lexer.
+func lexDockerfileHeredocBodies(s *dockerfileLexState, instr string, base int, heredocs [][]int) {
+	for _, h := range heredocs {
+		if !s.acceptByte('\n') {
+			return
+		}
+		s.emit(Whitespace)
+		bodyEnd, delimEnd := dockerfileHeredocBody(s.src, s.pos-1, base, h)
+		s.pos = bodyEnd
+		var l Lexer
+		if instr == "RUN" {
+			l = shellLexer{}
+			if r := lookupShebang(s.src[s.start:s.pos]); r != nil {
+				l = r.lexer
+			}
+		}
+		s.delegate(l, String)
+		s.pos = delimEnd
+		s.emit(String)
+	}
+}
+
+// lexDockerfileFlags lexes the flags that precede the arguments of an
+// instruction, such as --from=build.
+func lexDockerfileFlags(s *dockerfileLexState, end int) {
+	for s.pos < end {
+		s.acceptWhile(func(r rune) bool { return r == ' ' || r == '\t' })
+		s.emit(Whitespace)
+		if !s.hasPrefix("--") {
+			return
+		}
+		s.acceptWhile(func(r rune) bool { return r != '=' && r != ' ' && r != '\t' && r != '\n' })
+		s.emit(Builtin)
+		if s.acceptByte('=') {
+			s.emit(Punctuation)
+			for s.pos < end && !isDockerfileSpace(s.peek(0)) {
+				s.pos++
+			}
+			s.emit(String)
+		}
+	}
+}
+
+func isDockerfileSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\r' || c == '\n'
+}
+
+// lexDockerfileWords lexes the arguments of an instruction up to end. The
+// names of the variables that ARG and ENV set, and the keys of LABEL, are
+// highlighted as Parameter, and their values as String.
+func lexDockerfileWords(s *dockerfileLexState, instr string, end int) {
+	word := 0 // the index of the word at pos
+	for s.pos < end {
+		c := s.peek(0)
+		switch {
+		case c == s.escape && (s.pos+1 >= end || isDockerfileSpace(s.peek(1))):
+			// A line continuation.
+			s.pos++
+			s.emit(Punctuation)
+			continue
+		case isDockerfileSpace(c):
+			for s.pos < end && isDockerfileSpace(s.peek(0)) {
+				s.pos++
+			}
+			s.emit(Whitespace)
+			if s.peek(0) == '#' && s.atLineStart() {
+				// A comment line within the instruction.
+				for s.pos < end && s.peek(0) != '\n' {
+					s.pos++
+				}
+				s.emit(Comment)
+			}
+			word++
+			continue
+		case c == '"':
+			lexShellDoubleQuoted(s.lexState)
+		case c == '\'':
+			s.pos++
+			s.acceptQuoted("'", false, true)
+			s.emit(String)
+		case c == '$' && lexShellExpansion(s.lexState):
+		case s.hasPrefix("<<") && dockerfileHeredoc.Match(s.src[s.pos:end]):
+			s.pos += len(dockerfileHeredoc.Find(s.src[s.pos:end]))
+			s.emit(String)
+		default:
+			lexDockerfileWord(s, instr, word, end)
+		}
+	}
+}
+
+// lexDockerfileWord lexes the unquoted part of the word at pos, which is the
+// index-th word of the arguments of instr.
+func lexDockerfileWord(s *dockerfileLexState, instr string, index, end int) {
+	atWordStart := s.start == 0 || isDockerfileSpace(s.src[s.start-1])
+	for s.pos < end && !isDockerfileSpace(s.peek(0)) && strings.IndexByte("\"'$=", s.peek(0)) < 0 {
+		s.pos++
+	}
+	if s.pos == s.start {
+		s.pos++
+		s.emit(Punctuation)
+		return
+	}
+	switch instr {
+	case "ARG", "ENV", "LABEL":
+		if atWordStart && (s.peek(0) == '=' || instr != "LABEL" && index == 0) {
+			s.emit(Parameter)
+			return
+		}
+		s.emit(String)
+	case "FROM":
+		switch {
+		case strings.EqualFold(s.text(), "as"):
+			s.emit(Keyword)
+		case index == 0:
+			s.emit(String) // the image
+		default:
+			s.emit(Constant) // the name of the build stage
+		}
+	default:
+		s.emit(Plaintext)
+	}
+}
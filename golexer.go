@@ -0,0 +1,197 @@
+package syntaxhighlight
+
+import (
+	"go/scanner"
+	"go/token"
+	"unicode"
+)
+
+// goLexer tokenizes Go source code using go/scanner, so that operators,
+// literals and comments are split exactly as the Go compiler splits them.
+type goLexer struct{}
+
+func init() {
+	Register(LexerConfig{
+		Name:      "go",
+		Aliases:   []string{"golang"},
+		Filenames: []string{"*.go"},
+		MimeTypes: []string{"text/x-go", "text/x-gosrc"},
+	}, goLexer{})
+}
+
+func (goLexer) Tokens(src []byte) ([]Token, error) {
+	fset := token.NewFileSet()
+	file := fset.AddFile("", fset.Base(), len(src))
+
+	var s scanner.Scanner
+	s.Init(file, src, nil, scanner.ScanComments)
+
+	type scanned struct {
+		offset int
+		tok    token.Token
+		lit    string
+	}
+	var scannedToks []scanned
+	for {
+		pos, tok, lit := s.Scan()
+		if tok == token.EOF {
+			break
+		}
+		if tok == token.SEMICOLON && lit == "\n" {
+			// Automatically inserted semicolon; the newline is whitespace.
+			continue
+		}
+		scannedToks = append(scannedToks, scanned{file.Offset(pos), tok, lit})
+	}
+
+	var (
+		toks []Token
+		end  int // offset of the end of the last token
+
+		prev   token.Token // last non-comment token
+		braces []bool      // for each open brace, whether it opens a struct body
+	)
+	for i, st := range scannedToks {
+		text := st.lit
+		if text == "" {
+			text = st.tok.String()
+		}
+		if st.offset+len(text) > len(src) || string(src[st.offset:st.offset+len(text)]) != text {
+			// go/scanner strips carriage returns from raw strings and
+			// comments; the token then extends to the last non-space
+			// character before the next token.
+			next := len(src)
+			if i+1 < len(scannedToks) {
+				next = scannedToks[i+1].offset
+			}
+			for next > st.offset && unicode.IsSpace(rune(src[next-1])) {
+				next--
+			}
+			text = string(src[st.offset:next])
+		}
+
+		toks = appendWhitespace(toks, src, end, st.offset)
+		end = st.offset + len(text)
+
+		switch {
+		case st.tok == token.LBRACE:
+			braces = append(braces, prev == token.STRUCT)
+		case st.tok == token.RBRACE && len(braces) > 0:
+			braces = braces[:len(braces)-1]
+		}
+
+		if st.tok == token.STRING && len(braces) > 0 && braces[len(braces)-1] && isGoStructTagPosition(prev) {
+			toks = appendGoStructTag(toks, st.offset, text)
+		} else {
+			toks = append(toks, Token{Kind: goTokenKind(st.tok, st.lit, prev), Offset: st.offset, Text: text})
+		}
+
+		if st.tok != token.COMMENT {
+			prev = st.tok
+		}
+	}
+	toks = appendWhitespace(toks, src, end, len(src))
+
+	return toks, nil
+}
+
+func (goLexer) keywordTable() *keywordTable { return goKeywords }
+
+// appendWhitespace appends the source between start and end, which go/scanner
+// skipped, as a Whitespace token.
+func appendWhitespace(toks []Token, src []byte, start, end int) []Token {
+	if end > start {
+		toks = append(toks, Token{Kind: Whitespace, Offset: start, Text: string(src[start:end])})
+	}
+	return toks
+}
+
+// goTokenKind returns the kind of a Go token. prev is the last non-comment
+// token before it.
+func goTokenKind(tok token.Token, lit string, prev token.Token) Kind {
+	switch {
+	case tok == token.COMMENT:
+		return Comment
+	case tok == token.IDENT:
+		if kind, ok := goKeywords.kind(lit); ok {
+			return kind
+		}
+		if prev == token.TYPE {
+			return Type
+		}
+		return Plaintext
+	case tok.IsKeyword():
+		return Keyword
+	case tok == token.INT, tok == token.FLOAT, tok == token.IMAG:
+		return Decimal
+	case tok == token.CHAR, tok == token.STRING:
+		return String
+	}
+	return Punctuation
+}
+
+// isGoStructTagPosition reports whether a string literal following the token
+// prev in a struct body is a field tag (rather than, say, part of an
+// embedded field's type, which can't be a string).
+func isGoStructTagPosition(prev token.Token) bool {
+	switch prev {
+	case token.IDENT, token.RBRACK, token.RPAREN, token.RBRACE, token.MUL:
+		return true
+	}
+	return false
+}
+
+// appendGoStructTag appends the tokens of a struct field tag. Tags in the
+// conventional `key:"value" key:"value"` format (see reflect.StructTag) have
+// their keys highlighted as HTMLAttrName and values as HTMLAttrValue; other
+// tags are a single String.
+func appendGoStructTag(toks []Token, offset int, tag string) []Token {
+	if len(tag) < 2 || tag[0] != '`' || tag[len(tag)-1] != '`' {
+		return append(toks, Token{Kind: String, Offset: offset, Text: tag})
+	}
+
+	var tagToks []Token
+	emit := func(kind Kind, start, end int) {
+		if end > start {
+			tagToks = append(tagToks, Token{Kind: kind, Offset: offset + start, Text: tag[start:end]})
+		}
+	}
+	emit(String, 0, 1)
+	i, n := 1, len(tag)-1
+	for i < n {
+		start := i
+		for i < n && tag[i] == ' ' {
+			i++
+		}
+		emit(Whitespace, start, i)
+		if i == n {
+			break
+		}
+
+		start = i
+		for i < n && tag[i] > ' ' && tag[i] != ':' && tag[i] != '"' && tag[i] != 0x7f {
+			i++
+		}
+		if i == start || i+1 >= n || tag[i] != ':' || tag[i+1] != '"' {
+			return append(toks, Token{Kind: String, Offset: offset, Text: tag})
+		}
+		emit(HTMLAttrName, start, i)
+		emit(Punctuation, i, i+1)
+		i++
+
+		start = i
+		for i++; i < n && tag[i] != '"'; i++ {
+			if tag[i] == '\\' {
+				i++
+			}
+		}
+		if i >= n {
+			return append(toks, Token{Kind: String, Offset: offset, Text: tag})
+		}
+		i++
+		emit(HTMLAttrValue, start, i)
+	}
+	emit(String, n, n+1)
+
+	return append(toks, tagToks...)
+}
@@ -0,0 +1,48 @@
+package syntaxhighlight
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestGoLexer(t *testing.T) {
+	tests := []struct {
+		src  string
+		want []Token
+	}{
+		{"a:=2", []Token{
+			{Plaintext, 0, "a"}, {Punctuation, 1, ":="}, {Decimal, 3, "2"},
+		}},
+		{"x = 1i", []Token{
+			{Plaintext, 0, "x"}, {Whitespace, 1, " "}, {Punctuation, 2, "="},
+			{Whitespace, 3, " "}, {Decimal, 4, "1i"},
+		}},
+		{"s := `a\r\nb`\r\n", []Token{
+			{Plaintext, 0, "s"}, {Whitespace, 1, " "}, {Punctuation, 2, ":="},
+			{Whitespace, 4, " "}, {String, 5, "`a\r\nb`"}, {Whitespace, 11, "\r\n"},
+		}},
+		{"// a\r\nx", []Token{
+			{Comment, 0, "// a"}, {Whitespace, 4, "\r\n"}, {Plaintext, 6, "x"},
+		}},
+		{"type T string", []Token{
+			{Keyword, 0, "type"}, {Whitespace, 4, " "}, {Type, 5, "T"},
+			{Whitespace, 6, " "}, {Type, 7, "string"},
+		}},
+		{"struct{F int `k:\"v\"`}", []Token{
+			{Keyword, 0, "struct"}, {Punctuation, 6, "{"}, {Plaintext, 7, "F"},
+			{Whitespace, 8, " "}, {Type, 9, "int"}, {Whitespace, 12, " "},
+			{String, 13, "`"}, {HTMLAttrName, 14, "k"}, {Punctuation, 15, ":"},
+			{HTMLAttrValue, 16, `"v"`}, {String, 19, "`"}, {Punctuation, 20, "}"},
+		}},
+	}
+	for _, test := range tests {
+		got, err := goLexer{}.Tokens([]byte(test.src))
+		if err != nil {
+			t.Fatal(err)
+		}
+		checkTokens(t, test.src, []byte(test.src), got)
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("%q:\ngot  %v\nwant %v", test.src, got, test.want)
+		}
+	}
+}
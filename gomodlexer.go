@@ -0,0 +1,165 @@
+package syntaxhighlight
+
+import (
+	"regexp"
+	"strings"
+)
+
+// goModLexer tokenizes go.mod and go.work files. Directives are highlighted
+// as Keyword, module paths as Package, versions as Decimal (or Literal, for
+// pseudo-versions, which name commits rather than releases), local paths as
+// String and "// indirect" comments as Decorator.
+type goModLexer struct{}
+
+// goSumLexer tokenizes go.sum files, whose lines hold a module path, a
+// version and a hash.
+type goSumLexer struct{}
+
+func init() {
+	Register(LexerConfig{
+		Name:      "gomod",
+		Aliases:   []string{"go.mod"},
+		Filenames: []string{"go.mod"},
+	}, goModLexer{})
+	Register(LexerConfig{
+		Name:      "gowork",
+		Aliases:   []string{"go.work"},
+		Filenames: []string{"go.work"},
+	}, goModLexer{})
+	Register(LexerConfig{
+		Name:      "gosum",
+		Aliases:   []string{"go.sum"},
+		Filenames: []string{"go.sum", "go.work.sum"},
+	}, goSumLexer{})
+}
+
+var (
+	// goModVersion matches a semantic version, such as v1.2.3-rc.1 or
+	// v2.0.0+incompatible.
+	goModVersion = regexp.MustCompile(`^v\d+(\.\d+(\.\d+(-[0-9A-Za-z.-]+)?)?)?(\+incompatible)?$`)
+
+	// goModPseudoVersion matches the suffix of a pseudo-version, such as
+	// v0.0.0-20191109021931-daa7c04131f5, which has the time and hash of
+	// a commit.
+	goModPseudoVersion = regexp.MustCompile(`[-.]\d{14}-[0-9a-f]{12}(\+incompatible)?$`)
+
+	// goModGoVersion matches a Go version, such as 1.21 or go1.22rc1.
+	goModGoVersion = regexp.MustCompile(`^(go)?\d+(\.\d+)*((rc|beta)\d+)?$`)
+)
+
+// goModLexState is the state of the go.mod lexer.
+type goModLexState struct {
+	*lexState
+	block bool // whether pos is in a block, such as require ( ... )
+}
+
+func (goModLexer) Tokens(src []byte) ([]Token, error) {
+	s := &goModLexState{lexState: newLexState(src)}
+	for !s.eof() {
+		lexGoMod(s)
+	}
+	return s.toks, nil
+}
+
+func (goModLexer) keywordTable() *keywordTable { return goModKeywords }
+
+// lexGoMod lexes a single go.mod token.
+func lexGoMod(s *goModLexState) {
+	if s.lexWhitespace() {
+		return
+	}
+	switch c := s.peek(0); {
+	case s.hasPrefix("//"):
+		s.acceptLine()
+		if strings.TrimSpace(s.text()[2:]) == "indirect" {
+			s.emit(Decorator)
+		} else {
+			s.emit(Comment)
+		}
+	case c == '"' || c == '`':
+		s.pos++
+		s.acceptQuoted(string(c), c == '"', false)
+		s.emit(String)
+	case c == '(':
+		s.pos++
+		s.emit(Punctuation)
+		s.block = true
+	case c == ')':
+		s.pos++
+		s.emit(Punctuation)
+		s.block = false
+	case s.hasPrefix("=>"):
+		s.pos += 2
+		s.emit(Punctuation)
+	case c == '[' || c == ']' || c == ',':
+		s.pos++
+		s.emit(Punctuation)
+	default:
+		acceptGoModWord(s.lexState, true)
+		if _, ok := goModKeywords.kind(s.text()); ok && s.atLineStart() && !s.block {
+			s.emit(Keyword)
+		} else {
+			s.emit(goModWordKind(s.text()))
+		}
+	}
+}
+
+// acceptGoModWord advances past a module path, version or other word,
+// which ends before a comment if comments is set.
+func acceptGoModWord(s *lexState, comments bool) {
+	for !s.eof() && !(comments && s.hasPrefix("//")) {
+		r, w := s.peekRune()
+		if r == ' ' || r == '\t' || r == '\r' || r == '\n' || strings.ContainsRune("()[],\"`", r) {
+			break
+		}
+		s.pos += w
+	}
+	if s.pos == s.start {
+		_, w := s.peekRune()
+		s.pos += w
+	}
+}
+
+// goModWordKind returns the kind of a word that isn't a directive.
+func goModWordKind(word string) Kind {
+	switch {
+	case goModVersion.MatchString(word) && goModPseudoVersion.MatchString(word):
+		return Literal
+	case goModVersion.MatchString(word), goModGoVersion.MatchString(word):
+		return Decimal
+	case strings.HasPrefix(word, "./"), strings.HasPrefix(word, "../"), strings.HasPrefix(word, "/"), word == ".", word == "..":
+		return String // a local path, as in replace and use directives
+	case strings.ContainsAny(word, "=<>"):
+		return Plaintext // a setting of godebug, or an operator
+	}
+	return Package
+}
+
+func (goSumLexer) Tokens(src []byte) ([]Token, error) {
+	s := newLexState(src)
+	field := 0 // the index of the field at pos on its line
+	for !s.eof() {
+		if s.lexWhitespace() {
+			if strings.Contains(s.toks[len(s.toks)-1].Text, "\n") {
+				field = 0
+			}
+			continue
+		}
+		acceptGoModWord(s, false)
+		switch word := s.text(); field {
+		case 0:
+			s.emit(Package)
+		case 1:
+			// The version of the module, or of just its go.mod file.
+			end := s.pos
+			s.pos = s.start + len(strings.TrimSuffix(word, "/go.mod"))
+			s.emit(goModWordKind(s.text()))
+			s.pos = end
+			s.emit(Plaintext)
+		default:
+			s.emit(String)
+		}
+		field++
+	}
+	return s.toks, nil
+}
@@ -0,0 +1,47 @@
+package syntaxhighlight
+
+import (
+	"reflect"
+	"testing"
+)
+
+// TestGoModLexer tests the go.mod lexer, whose golden output can't live in
+// testdata: a go.mod file there would make it a module of its own.
+func TestGoModLexer(t *testing.T) {
+	tests := []struct {
+		src  string
+		want []Token
+	}{
+		{"module example.com/m", []Token{
+			{Keyword, 0, "module"}, {Whitespace, 6, " "}, {Package, 7, "example.com/m"},
+		}},
+		{"go 1.22\ntoolchain go1.22.3", []Token{
+			{Keyword, 0, "go"}, {Whitespace, 2, " "}, {Decimal, 3, "1.22"}, {Whitespace, 7, "\n"},
+			{Keyword, 8, "toolchain"}, {Whitespace, 17, " "}, {Decimal, 18, "go1.22.3"},
+		}},
+		{"require (\n\ta.com/b v0.0.0-20160123013949-f4cad6c6324d // indirect\n)", []Token{
+			{Keyword, 0, "require"}, {Whitespace, 7, " "}, {Punctuation, 8, "("}, {Whitespace, 9, "\n\t"},
+			{Package, 11, "a.com/b"}, {Whitespace, 18, " "}, {Literal, 19, "v0.0.0-20160123013949-f4cad6c6324d"},
+			{Whitespace, 53, " "}, {Decorator, 54, "// indirect"}, {Whitespace, 65, "\n"}, {Punctuation, 66, ")"},
+		}},
+		{"replace a.com/b v1.0.0+incompatible => ../b // local", []Token{
+			{Keyword, 0, "replace"}, {Whitespace, 7, " "}, {Package, 8, "a.com/b"}, {Whitespace, 15, " "},
+			{Decimal, 16, "v1.0.0+incompatible"}, {Whitespace, 35, " "}, {Punctuation, 36, "=>"},
+			{Whitespace, 38, " "}, {String, 39, "../b"}, {Whitespace, 43, " "}, {Comment, 44, "// local"},
+		}},
+		{"retract [v1.0.0, v1.1.0-rc.1]", []Token{
+			{Keyword, 0, "retract"}, {Whitespace, 7, " "}, {Punctuation, 8, "["}, {Decimal, 9, "v1.0.0"},
+			{Punctuation, 15, ","}, {Whitespace, 16, " "}, {Decimal, 17, "v1.1.0-rc.1"}, {Punctuation, 28, "]"},
+		}},
+	}
+	for _, test := range tests {
+		got, err := Lookup("gomod").Tokens([]byte(test.src))
+		if err != nil {
+			t.Fatal(err)
+		}
+		checkTokens(t, test.src, []byte(test.src), got)
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("%q:\ngot  %v\nwant %v", test.src, got, test.want)
+		}
+	}
+}
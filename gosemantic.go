@@ -0,0 +1,197 @@
+package syntaxhighlight
+
+import (
+	"go/ast"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"path/filepath"
+)
+
+// GoSemanticLexer is a lexer for Go that type-checks the source to refine the
+// kinds of identifiers beyond what the Go lexer can tell: functions, methods,
+// fields, parameters, constants, package names and type parameters are given
+// their own kinds. It is opt-in, since type-checking is much slower than
+// lexing:
+//
+//	AsHTML(src, UseLexer(GoSemanticLexer{Filename: "main.go"}))
+//
+// Sources that don't parse or type-check are highlighted as well as possible;
+// errors never fail highlighting.
+type GoSemanticLexer struct {
+	// Filename is the name of the source file. It is used to exclude the
+	// file itself when type-checking its package directory.
+	Filename string
+
+	// Dir, if set, is the package directory of the source. The other Go
+	// files of the same package in it are type-checked along with the
+	// source, so that identifiers declared in them are resolved.
+	Dir string
+
+	// Importer imports the packages the source imports. If nil,
+	// importer.Default() is used.
+	Importer types.Importer
+}
+
+func (l GoSemanticLexer) Tokens(src []byte) ([]Token, error) {
+	toks, err := goLexer{}.Tokens(src)
+	if err != nil {
+		return nil, err
+	}
+
+	fset := token.NewFileSet()
+	f, _ := parser.ParseFile(fset, l.Filename, src, 0)
+	if f == nil {
+		return toks, nil
+	}
+	kinds := l.identKinds(fset, f)
+
+	// Only identifier tokens start at the offsets of identifiers.
+	for i, tok := range toks {
+		if kind, ok := kinds[tok.Offset]; ok {
+			toks[i].Kind = kind
+		}
+	}
+	return toks, nil
+}
+
+// identKinds type-checks f (along with the rest of its package, if l.Dir is
+// set) and returns the kinds of the identifiers in f, by offset.
+func (l GoSemanticLexer) identKinds(fset *token.FileSet, f *ast.File) map[int]Kind {
+	files := []*ast.File{f}
+	if l.Dir != "" {
+		files = append(files, l.parsePackageDir(fset, f.Name.Name)...)
+	}
+
+	info := &types.Info{
+		Defs: make(map[*ast.Ident]types.Object),
+		Uses: make(map[*ast.Ident]types.Object),
+	}
+	conf := types.Config{
+		Importer: l.Importer,
+		Error:    func(error) {}, // keep going; partial information is still useful
+	}
+	if conf.Importer == nil {
+		conf.Importer = importer.Default()
+	}
+	conf.Check(f.Name.Name, fset, files, info)
+
+	params := make(map[types.Object]bool)
+	ast.Inspect(f, func(n ast.Node) bool {
+		if ft, ok := n.(*ast.FuncType); ok {
+			for _, fields := range []*ast.FieldList{ft.Params, ft.Results} {
+				addParams(params, info, fields)
+			}
+		}
+		if fd, ok := n.(*ast.FuncDecl); ok {
+			addParams(params, info, fd.Recv)
+		}
+		return true
+	})
+
+	file := fset.File(f.Pos())
+	kinds := make(map[int]Kind)
+	add := func(id *ast.Ident, obj types.Object) {
+		if kind, ok := goObjectKind(obj, params); ok && fset.File(id.Pos()) == file {
+			kinds[file.Offset(id.Pos())] = kind
+		}
+	}
+	for id, obj := range info.Defs {
+		add(id, obj)
+	}
+	for id, obj := range info.Uses {
+		add(id, obj)
+	}
+
+	// Calls of functions that could not be resolved, such as those in
+	// packages that failed to import, are still recognizable as calls.
+	ast.Inspect(f, func(n ast.Node) bool {
+		call, ok := n.(*ast.CallExpr)
+		if !ok {
+			return true
+		}
+		var id *ast.Ident
+		switch fun := call.Fun.(type) {
+		case *ast.Ident:
+			id = fun
+		case *ast.SelectorExpr:
+			id = fun.Sel
+		}
+		if id != nil && info.Uses[id] == nil && info.Defs[id] == nil {
+			kinds[file.Offset(id.Pos())] = Function
+		}
+		return true
+	})
+
+	return kinds
+}
+
+// parsePackageDir parses the Go files in l.Dir, other than l.Filename, that
+// belong to package pkg.
+func (l GoSemanticLexer) parsePackageDir(fset *token.FileSet, pkg string) []*ast.File {
+	paths, err := filepath.Glob(filepath.Join(l.Dir, "*.go"))
+	if err != nil {
+		return nil
+	}
+	var files []*ast.File
+	for _, path := range paths {
+		if l.Filename != "" && filepath.Base(path) == filepath.Base(l.Filename) {
+			continue
+		}
+		f, err := parser.ParseFile(fset, path, nil, 0)
+		if err != nil || f.Name.Name != pkg {
+			continue
+		}
+		files = append(files, f)
+	}
+	return files
+}
+
+// addParams adds the objects declared by the parameter list fields to params.
+func addParams(params map[types.Object]bool, info *types.Info, fields *ast.FieldList) {
+	if fields == nil {
+		return
+	}
+	for _, field := range fields.List {
+		for _, name := range field.Names {
+			if obj := info.Defs[name]; obj != nil {
+				params[obj] = true
+			}
+		}
+	}
+}
+
+// goObjectKind returns the kind of identifiers denoting obj, and whether
+// semantic analysis has anything to add to the kind given by the Go lexer.
+func goObjectKind(obj types.Object, params map[types.Object]bool) (Kind, bool) {
+	if obj == nil || obj.Pkg() == nil {
+		// Predeclared identifiers are already highlighted by the lexer.
+		return 0, false
+	}
+	switch obj := obj.(type) {
+	case *types.PkgName:
+		return Package, true
+	case *types.Func:
+		if sig, ok := obj.Type().(*types.Signature); ok && sig.Recv() != nil {
+			return Method, true
+		}
+		return Function, true
+	case *types.Var:
+		if obj.IsField() {
+			return Field, true
+		}
+		if params[obj] {
+			return Parameter, true
+		}
+		return Plaintext, true
+	case *types.Const:
+		return Constant, true
+	case *types.TypeName:
+		if isTypeParam(obj.Type()) {
+			return TypeParameter, true
+		}
+		return Type, true
+	}
+	return 0, false
+}
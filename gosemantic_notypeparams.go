@@ -0,0 +1,12 @@
+//go:build !go1.18
+// +build !go1.18
+
+package syntaxhighlight
+
+import "go/types"
+
+// isTypeParam reports whether t is a type parameter. Before Go 1.18, there
+// are none.
+func isTypeParam(t types.Type) bool {
+	return false
+}
@@ -0,0 +1,109 @@
+//go:build go1.18
+// +build go1.18
+
+package syntaxhighlight
+
+import (
+	"fmt"
+	"go/types"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// failingImporter fails to import any package, so that tests don't depend
+// on export data being available.
+type failingImporter struct{}
+
+func (failingImporter) Import(path string) (*types.Package, error) {
+	return nil, fmt.Errorf("can't import %q", path)
+}
+
+func TestGoSemanticLexer(t *testing.T) {
+	src := []byte(`package p
+
+import "strings"
+
+const limit = 10
+
+type point struct{ x, y int }
+
+func (p point) add(q point) point { return point{x: p.x + q.x, y: p.y + q.y} }
+
+func first[T any](s []T) T { return s[0] }
+
+func run(n int) string {
+	v := point{}.add(point{})
+	_ = first([]int{v.x, limit, n})
+	return strings.Repeat("a", n)
+}
+`)
+	want := map[string]Kind{
+		"strings": Package,
+		"limit":   Constant,
+		"point":   Type,
+		"x":       Field,
+		"add":     Method,
+		"q":       Parameter,
+		"p":       Parameter,
+		"first":   Function,
+		"T":       TypeParameter,
+		"s":       Parameter,
+		"run":     Function,
+		"n":       Parameter,
+		"v":       Plaintext,
+		"Repeat":  Function,
+		"int":     Type,
+		"any":     Type,
+	}
+
+	toks, err := GoSemanticLexer{Importer: failingImporter{}}.Tokens(src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkTokens(t, "GoSemanticLexer", src, toks)
+
+	for _, tok := range toks[3:] { // skip the package clause
+		if kind, ok := want[tok.Text]; ok && tok.Kind != kind {
+			t.Errorf("%q at offset %d: got %#v, want %#v", tok.Text, tok.Offset, tok.Kind, kind)
+		}
+	}
+}
+
+func TestGoSemanticLexer_invalid(t *testing.T) {
+	src := []byte("package p\n\nfunc f( {\n\tg(\n")
+	toks, err := GoSemanticLexer{Importer: failingImporter{}}.Tokens(src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkTokens(t, "GoSemanticLexer", src, toks)
+}
+
+func TestGoSemanticLexer_dir(t *testing.T) {
+	dir, err := ioutil.TempDir("", "syntaxhighlight")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	if err := ioutil.WriteFile(filepath.Join(dir, "b.go"), []byte("package p\n\nconst max = 1\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	src := []byte("package p\n\nvar x = max\n")
+	for _, test := range []struct {
+		dir  string
+		want Kind
+	}{
+		{"", Builtin}, // the predeclared max function
+		{dir, Constant},
+	} {
+		toks, err := GoSemanticLexer{Filename: "a.go", Dir: test.dir, Importer: failingImporter{}}.Tokens(src)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if last := toks[len(toks)-2]; last.Text != "max" || last.Kind != test.want {
+			t.Errorf("Dir %q: got %+v, want max as %#v", test.dir, last, test.want)
+		}
+	}
+}
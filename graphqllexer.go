@@ -0,0 +1,186 @@
+package syntaxhighlight
+
+// graphqlLexer tokenizes GraphQL schemas and operations. Types are
+// highlighted as Type, the names of operations and fragments as Function,
+// fields as Field, arguments and $variables as Parameter, enum values as
+// Constant and @directives as Decorator. Descriptions, which may be """block
+// strings""", are highlighted as String.
+type graphqlLexer struct{}
+
+func init() {
+	Register(LexerConfig{
+		Name:      "graphql",
+		Aliases:   []string{"gql"},
+		Filenames: []string{"*.graphql", "*.graphqls", "*.gql"},
+		MimeTypes: []string{"application/graphql"},
+	}, graphqlLexer{})
+}
+
+// graphqlLexState is the state of the GraphQL lexer.
+type graphqlLexState struct {
+	*lexState
+	decl    string // the keyword of the top-level definition at pos, such as "type" or "query"
+	braces  int    // the depth of selection sets and field lists
+	parens  int    // the depth of argument lists
+	typeRef bool   // whether a type, such as [User!], is expected at pos
+}
+
+func (graphqlLexer) Tokens(src []byte) ([]Token, error) {
+	s := &graphqlLexState{lexState: newLexState(src)}
+	for !s.eof() {
+		lexGraphQL(s)
+	}
+	return s.toks, nil
+}
+
+func (graphqlLexer) keywordTable() *keywordTable { return graphqlKeywords }
+
+// lexGraphQL lexes a single GraphQL token.
+func lexGraphQL(s *graphqlLexState) {
+	if s.lexWhitespace() {
+		return
+	}
+	c := s.peek(0)
+	switch {
+	case c == '#':
+		s.acceptLine()
+		s.emit(Comment)
+	case s.hasPrefix(`"""`):
+		s.pos += 3
+		s.acceptQuoted(`"""`, true, true)
+		s.emit(String)
+	case c == '"':
+		s.pos++
+		s.acceptQuoted(`"`, true, false)
+		s.emit(String)
+	case (c == '$' || c == '@') && isIdentStartByte(s.peek(1)):
+		s.pos++
+		s.acceptWhile(isIdentPart)
+		if c == '$' {
+			s.emit(Parameter)
+		} else {
+			s.emit(Decorator)
+		}
+	case isIdentStartByte(c):
+		s.acceptWhile(isIdentPart)
+		s.emit(graphqlIdentKind(s))
+		s.typeRef = false
+	case c == '-' && isDigit(s.peek(1)):
+		s.pos++
+		s.acceptNumber(0)
+		s.emit(Decimal)
+	case s.acceptNumber(0):
+		s.emit(Decimal)
+	case s.hasPrefix("..."):
+		s.pos += 3
+		s.emit(Punctuation)
+	default:
+		_, w := s.peekRune()
+		s.pos += w
+		prev, _ := s.last()
+		typeRef := s.typeRef
+		s.typeRef = false
+		switch c {
+		case '{':
+			if s.braces == 0 && s.decl == "" {
+				s.decl = "query" // a query without the keyword
+			}
+			s.braces++
+		case '}':
+			if s.braces--; s.braces == 0 {
+				s.decl = ""
+			}
+		case '(':
+			s.parens++
+		case ')':
+			s.parens--
+		case ':':
+			// Types follow the colons of field definitions and of
+			// definitions of variables, such as ($id: ID!).
+			s.typeRef = graphqlIsSchema(s.decl) || prev.Kind == Parameter && prev.Text[0] == '$'
+		case '[', ']', '!':
+			s.typeRef = typeRef // as in [User!]!
+		}
+		s.emit(Punctuation)
+	}
+}
+
+// graphqlIsSchema reports whether the top-level definition that starts with
+// keyword decl is a definition of the type system, rather than an operation
+// or fragment.
+func graphqlIsSchema(decl string) bool {
+	switch decl {
+	case "", "query", "mutation", "subscription", "fragment":
+		return false
+	}
+	return true
+}
+
+// graphqlIdentKind returns the kind of the name that is the current token.
+func graphqlIdentKind(s *graphqlLexState) Kind {
+	ident := s.text()
+	prev, _ := s.last()
+	next := graphqlNextByte(s)
+	kind, inTable := graphqlKeywords.kind(ident)
+
+	switch {
+	case s.typeRef:
+		return Type
+	case kind == Keyword && s.braces == 0 && s.parens == 0 && next != ':':
+		switch ident {
+		case "on", "implements", "repeatable":
+		default:
+			s.decl = ident // a definition starts, or follows extend
+		}
+		return Keyword
+	case ident == "on" && prev.Text == "...":
+		return Keyword // an inline fragment
+	case prev.Kind == Keyword:
+		switch prev.Text {
+		case "type", "interface", "union", "enum", "input", "scalar", "on", "implements":
+			if s.decl == "directive" {
+				return Constant // the location of a directive
+			}
+			return Type
+		case "query", "mutation", "subscription", "fragment":
+			return Function
+		}
+	case next == ':':
+		if s.parens > 0 {
+			return Parameter
+		}
+		return Field
+	case inTable && kind != Keyword:
+		return kind
+	}
+
+	switch {
+	case (prev.Text == "&" || prev.Text == "|" || prev.Text == "=") && s.braces == 0 && s.parens == 0:
+		if s.decl == "directive" {
+			return Constant // a location of a directive
+		}
+		return Type // an implemented interface, or a member of a union
+	case prev.Text == "...":
+		return Function // a fragment spread
+	case s.decl == "enum" && s.braces > 0:
+		return Constant
+	case s.parens > 0:
+		return Constant // an enum value, as the value of an argument
+	case s.braces > 0:
+		return Field
+	}
+	return Plaintext
+}
+
+// graphqlNextByte returns the first byte after pos that isn't whitespace or
+// a comma, which GraphQL ignores, or 0 at EOF.
+func graphqlNextByte(s *graphqlLexState) byte {
+	for i := s.pos; i < len(s.src); i++ {
+		switch s.src[i] {
+		case ' ', '\t', '\r', '\n', ',':
+			continue
+		}
+		return s.src[i]
+	}
+	return 0
+}
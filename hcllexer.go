@@ -0,0 +1,243 @@
+package syntaxhighlight
+
+import (
+	"bytes"
+	"regexp"
+	"strings"
+)
+
+// hclLexer tokenizes HCL, the configuration language of Terraform, Nomad and
+// Packer. Block types are highlighted as Keyword and their labels as Type,
+// attribute names and object keys as Field and function calls as Function.
+// The ${} interpolations and %{} directives of strings and heredocs are
+// highlighted as code.
+type hclLexer struct {
+	expr bool // whether the source is an expression, rather than a body of attributes and blocks
+}
+
+func init() {
+	Register(LexerConfig{
+		Name:      "hcl",
+		Aliases:   []string{"terraform", "tf"},
+		Filenames: []string{"*.hcl", "*.tf", "*.tfvars", "*.nomad", ".terraformrc", "terraform.rc"},
+		MimeTypes: []string{"text/x-hcl"},
+	}, hclLexer{})
+}
+
+var (
+	hclOperators = []string{"==", "!=", "<=", ">=", "&&", "||", "=>", "...", "::"}
+
+	// hclHeredoc matches the opening of a heredoc, such as <<EOT or <<-EOT,
+	// which ends its line.
+	hclHeredoc = regexp.MustCompile(`^<<-?([A-Za-z_][A-Za-z0-9_-]*)\r?\n`)
+)
+
+// hclLexState is the state of the HCL lexer.
+type hclLexState struct {
+	*lexState
+	bodies []bool // whether each enclosing bracket is the body of a block
+	labels bool   // whether pos is in the labels of a block, after its type
+}
+
+func (l hclLexer) Tokens(src []byte) ([]Token, error) {
+	s := &hclLexState{lexState: newLexState(src)}
+	if l.expr {
+		s.bodies = []bool{false}
+	}
+	for !s.eof() {
+		lexHCL(s)
+	}
+	return s.toks, nil
+}
+
+func (hclLexer) keywordTable() *keywordTable { return hclKeywords }
+
+// inBody reports whether pos is in a body, where attributes and blocks are
+// defined, rather than in an expression.
+func (s *hclLexState) inBody() bool {
+	return len(s.bodies) == 0 || s.bodies[len(s.bodies)-1]
+}
+
+// lexHCL lexes a single HCL token.
+func lexHCL(s *hclLexState) {
+	if s.lexWhitespace() {
+		if strings.Contains(s.toks[len(s.toks)-1].Text, "\n") {
+			s.labels = false
+		}
+		return
+	}
+	c := s.peek(0)
+	switch {
+	case c == '#' || s.hasPrefix("//"):
+		s.acceptLine()
+		s.emit(Comment)
+	case s.hasPrefix("/*"):
+		if s.acceptUntil("*/") {
+			s.pos += 2
+		}
+		s.emit(Comment)
+	case c == '"':
+		kind := String
+		if s.labels {
+			kind = Type
+		} else if hclIsKey(s) {
+			kind = Field
+		}
+		s.pos++
+		lexHCLTemplate(s.lexState, kind, true, func() bool { return s.peek(0) == '"' || s.peek(0) == '\n' })
+		s.acceptByte('"')
+		s.emit(kind)
+	case s.hasPrefix("<<") && lexHCLHeredoc(s):
+	case isIdentStartByte(c):
+		s.acceptWhile(func(r rune) bool { return isIdentPart(r) || r == '-' })
+		s.emit(hclIdentKind(s))
+	case s.acceptNumber(0):
+		s.emit(Decimal)
+	case s.acceptAny(hclOperators):
+		s.emit(Punctuation)
+	default:
+		_, w := s.peekRune()
+		s.pos += w
+		switch c {
+		case '{':
+			s.bodies = append(s.bodies, s.labels)
+		case '[', '(':
+			s.bodies = append(s.bodies, false)
+		case '}', ']', ')':
+			if len(s.bodies) > 0 {
+				s.bodies = s.bodies[:len(s.bodies)-1]
+			}
+		}
+		s.labels = false
+		s.emit(Punctuation)
+	}
+}
+
+// hclIdentKind returns the kind of the identifier that is the current token.
+func hclIdentKind(s *hclLexState) Kind {
+	ident := s.text()
+	if prev := s.lastN(2); len(prev) > 0 && prev[len(prev)-1].Text == "." {
+		if len(prev) == 2 && prev[0].Text == "var" {
+			return Parameter // an input variable
+		}
+		return Field
+	}
+	switch {
+	case s.inBody() && s.atLineStart():
+		if hclIsKey(s) {
+			return Field // an attribute
+		}
+		s.labels = true
+		return Keyword // the type of a block
+	case s.labels:
+		return Type
+	case hclIsKey(s):
+		return Field
+	}
+	kind, ok := hclKeywords.kind(ident)
+	if ok && kind != Builtin {
+		return kind
+	}
+	if i := s.pos; i < len(s.src) && s.src[i] == '(' || bytes.HasPrefix(s.src[i:], []byte("::")) {
+		return Function // a call of a function, or of a function of a provider
+	}
+	if ok {
+		return Builtin
+	}
+	return Plaintext
+}
+
+// hclIsKey reports whether the identifier or quoted string at start, the
+// first of its line, of a body or of an element of an object, is followed by
+// an "=" or ":", which makes it the name of an attribute or a key.
+func hclIsKey(s *hclLexState) bool {
+	if prev, ok := s.last(); ok && prev.Text != "{" && prev.Text != "," && !s.atLineStart() {
+		return false
+	}
+	i := s.start
+	if s.src[i] == '"' {
+		for i++; i < len(s.src) && s.src[i] != '"'; i++ {
+			if s.src[i] == '\\' || s.src[i] == '\n' {
+				i++
+			}
+		}
+		i++
+	} else {
+		for i < len(s.src) && (isIdentPart(rune(s.src[i])) || s.src[i] == '-') {
+			i++
+		}
+	}
+	for i < len(s.src) && (s.src[i] == ' ' || s.src[i] == '\t') {
+		i++
+	}
+	if i >= len(s.src) {
+		return false
+	}
+	next := s.src[i:]
+	return next[0] == ':' && !bytes.HasPrefix(next, []byte("::")) ||
+		next[0] == '=' && !bytes.HasPrefix(next, []byte("==")) && !bytes.HasPrefix(next, []byte("=>"))
+}
+
+// lexHCLHeredoc lexes a heredoc, such as <<EOT, its body, which is a
+// template, and its terminator, and reports whether one starts at pos.
+func lexHCLHeredoc(s *hclLexState) bool {
+	m := hclHeredoc.FindSubmatch(s.src[s.pos:])
+	if m == nil {
+		return false
+	}
+	s.pos += len(bytes.TrimRight(m[0], "\r\n"))
+	s.emit(String)
+	s.acceptByte('\r')
+	s.acceptByte('\n')
+	s.emit(Whitespace)
+
+	bodyEnd, termEnd := len(s.src), len(s.src)
+	for i := s.pos; i < len(s.src); {
+		lineEnd := bytes.IndexByte(s.src[i:], '\n')
+		if lineEnd < 0 {
+			lineEnd = len(s.src)
+		} else {
+			lineEnd += i
+		}
+		if bytes.Equal(bytes.TrimSpace(s.src[i:lineEnd]), m[1]) {
+			bodyEnd, termEnd = i, lineEnd
+			break
+		}
+		i = lineEnd + 1
+	}
+	// The body is lexed on the source cut at its end, so that an
+	// interpolation or directive that isn't closed doesn't run past it.
+	src := s.src
+	s.src = s.src[:bodyEnd]
+	lexHCLTemplate(s.lexState, String, false, func() bool { return false })
+	s.emit(String)
+	s.src = src
+	s.pos = termEnd
+	s.emit(String)
+	return true
+}
+
+// lexHCLTemplate lexes the contents of a quoted string or heredoc up to the
+// point where atEnd returns true, highlighting its ${} interpolations and
+// %{} directives as code and the rest as kind. Backslash escapes are only
+// recognized if escapes is set, as heredocs have none.
+func lexHCLTemplate(s *lexState, kind Kind, escapes bool, atEnd func() bool) {
+	for !s.eof() && !atEnd() {
+		switch {
+		case escapes && s.peek(0) == '\\' && s.pos+1 < len(s.src):
+			s.pos += 2
+		case s.hasPrefix("$${") || s.hasPrefix("%%{"):
+			s.pos += 3 // an escaped "${" or "%{"
+		case s.hasPrefix("${") || s.hasPrefix("%{"):
+			s.emit(kind)
+			s.pos += 2
+			s.emit(Punctuation)
+			s.delegateBalanced('{', '}', `"`, hclLexer{expr: true}, Plaintext)
+			if s.acceptByte('}') {
+				s.emit(Punctuation)
+			}
+		default:
+			s.pos++
+		}
+	}
+}
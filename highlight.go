@@ -1,6 +1,7 @@
-// Package syntaxhighlight provides syntax highlighting for code. It currently
-// uses a language-independent lexer and performs decently on JavaScript, Java,
-// Ruby, Python, Go, and C.
+// Package syntaxhighlight provides syntax highlighting for code. Source code is
+// split into tokens by a Lexer; lexers are registered per language (see
+// Register and Lookup). The language-independent FallbackLexer performs
+// decently on JavaScript, Java, Ruby, Python, Go, and C.
 package syntaxhighlight
 
 import (
@@ -34,6 +35,21 @@ const (
 	HTMLAttrName
 	HTMLAttrValue
 	Decimal
+	Builtin
+	Decorator
+	Preprocessor
+	Inserted
+	Deleted
+
+	// Kinds of identifiers that need semantic analysis to tell apart (see
+	// GoSemanticLexer).
+	Function
+	Method
+	Field
+	Parameter
+	Constant
+	Package
+	TypeParameter
 )
 
 //go:generate gostringer -type=Kind
@@ -59,9 +75,25 @@ type HTMLConfig struct {
 	HTMLAttrName  string
 	HTMLAttrValue string
 	Decimal       string
+	Builtin       string
+	Decorator     string
+	Preprocessor  string
+	Inserted      string
+	Deleted       string
+	Function      string
+	Method        string
+	Field         string
+	Parameter     string
+	Constant      string
+	Package       string
+	TypeParameter string
 	Whitespace    string
 
 	AsOrderedList bool
+
+	// Lexer is used to tokenize the source code. If nil, FallbackLexer is
+	// used.
+	Lexer Lexer
 }
 
 // HTMLPrinter implements Printer interface and is used to produce
@@ -95,6 +127,30 @@ func (c HTMLConfig) Class(kind Kind) string {
 		return c.HTMLAttrValue
 	case Decimal:
 		return c.Decimal
+	case Builtin:
+		return c.Builtin
+	case Decorator:
+		return c.Decorator
+	case Preprocessor:
+		return c.Preprocessor
+	case Inserted:
+		return c.Inserted
+	case Deleted:
+		return c.Deleted
+	case Function:
+		return c.Function
+	case Method:
+		return c.Method
+	case Field:
+		return c.Field
+	case Parameter:
+		return c.Parameter
+	case Constant:
+		return c.Constant
+	case Package:
+		return c.Package
+	case TypeParameter:
+		return c.TypeParameter
 	}
 	return ""
 }
@@ -175,8 +231,29 @@ func OrderedList() Option {
 	}
 }
 
+// UseLexer makes the source code be tokenized by the given lexer.
+//
+// Example:
+// AsHTML(input, UseLexer(myLexer))
+func UseLexer(l Lexer) Option {
+	return func(o *HTMLConfig) {
+		o.Lexer = l
+	}
+}
+
+// Language makes the source code be tokenized by the lexer registered under
+// the given name or alias. If there is none, FallbackLexer is used.
+//
+// Example:
+// AsHTML(input, Language("go"))
+func Language(name string) Option {
+	return UseLexer(Lookup(name))
+}
+
 // DefaultHTMLConfig provides class names that match those of google-code-prettify
-// (https://code.google.com/p/google-code-prettify/).
+// (https://code.google.com/p/google-code-prettify/). Builtin shares the class
+// of Keyword; the other kinds that prettify does not distinguish get classes
+// of their own.
 var DefaultHTMLConfig = HTMLConfig{
 	String:        "str",
 	Keyword:       "kwd",
@@ -190,6 +267,18 @@ var DefaultHTMLConfig = HTMLConfig{
 	HTMLAttrName:  "atn",
 	HTMLAttrValue: "atv",
 	Decimal:       "dec",
+	Builtin:       "kwd",
+	Decorator:     "ann",
+	Preprocessor:  "pre",
+	Inserted:      "ins",
+	Deleted:       "del",
+	Function:      "fun",
+	Method:        "mth",
+	Field:         "fld",
+	Parameter:     "par",
+	Constant:      "con",
+	Package:       "pkg",
+	TypeParameter: "tpa",
 	Whitespace:    "",
 }
 
@@ -197,7 +286,7 @@ func Print(s *scanner.Scanner, w io.Writer, p Printer) error {
 	tok := s.Scan()
 	for tok != scanner.EOF {
 		tokText := s.TokenText()
-		err := p.Print(w, tokenKind(tok, tokText), tokText)
+		err := p.Print(w, tokenKind(tok, tokText, fallbackKeywords), tokText)
 		if err != nil {
 			return err
 		}
@@ -208,26 +297,49 @@ func Print(s *scanner.Scanner, w io.Writer, p Printer) error {
 	return nil
 }
 
-func Annotate(src []byte, a Annotator) (annotate.Annotations, error) {
-	s := NewScanner(src)
+// PrintLexer tokenizes src with the lexer l and prints the tokens using p.
+// If l is nil, FallbackLexer is used.
+func PrintLexer(l Lexer, src []byte, w io.Writer, p Printer) error {
+	if l == nil {
+		l = FallbackLexer
+	}
+	toks, err := l.Tokens(src)
+	if err != nil {
+		return err
+	}
+	for _, tok := range toks {
+		if err := p.Print(w, tok.Kind, tok.Text); err != nil {
+			return err
+		}
+	}
+	return nil
+}
 
-	var anns annotate.Annotations
-	read := 0
+// Annotate annotates src using FallbackLexer.
+func Annotate(src []byte, a Annotator) (annotate.Annotations, error) {
+	return AnnotateLexer(FallbackLexer, src, a)
+}
 
-	tok := s.Scan()
-	for tok != scanner.EOF {
-		tokText := s.TokenText()
+// AnnotateLexer tokenizes src with the lexer l and annotates the tokens
+// using a. If l is nil, FallbackLexer is used.
+func AnnotateLexer(l Lexer, src []byte, a Annotator) (annotate.Annotations, error) {
+	if l == nil {
+		l = FallbackLexer
+	}
+	toks, err := l.Tokens(src)
+	if err != nil {
+		return nil, err
+	}
 
-		ann, err := a.Annotate(read, tokenKind(tok, tokText), tokText)
+	var anns annotate.Annotations
+	for _, tok := range toks {
+		ann, err := a.Annotate(tok.Offset, tok.Kind, tok.Text)
 		if err != nil {
 			return nil, err
 		}
-		read += len(tokText)
 		if ann != nil {
 			anns = append(anns, ann)
 		}
-
-		tok = s.Scan()
 	}
 
 	return anns, nil
@@ -235,7 +347,7 @@ func Annotate(src []byte, a Annotator) (annotate.Annotations, error) {
 
 // AsHTML converts source code into an HTML-highlighted version;
 // It accepts optional configuration parameters to control rendering
-// (see OrderedList as one example)
+// (see OrderedList and Language as examples)
 func AsHTML(src []byte, options ...Option) ([]byte, error) {
 	opt := DefaultHTMLConfig
 	for _, f := range options {
@@ -246,7 +358,7 @@ func AsHTML(src []byte, options ...Option) ([]byte, error) {
 	if opt.AsOrderedList {
 		buf.Write([]byte("<ol>\n<li>"))
 	}
-	err := Print(NewScanner(src), &buf, HTMLPrinter(opt))
+	err := PrintLexer(opt.Lexer, src, &buf, HTMLPrinter(opt))
 	if opt.AsOrderedList {
 		buf.Write([]byte("</li>\n</ol>"))
 	}
@@ -271,11 +383,11 @@ func NewScannerReader(src io.Reader) *scanner.Scanner {
 	return &s
 }
 
-func tokenKind(tok rune, tokText string) Kind {
+func tokenKind(tok rune, tokText string, table *keywordTable) Kind {
 	switch tok {
 	case scanner.Ident:
-		if _, isKW := keywords[tokText]; isKW {
-			return Keyword
+		if kind, ok := table.kind(tokText); ok {
+			return kind
 		}
 		if r, _ := utf8.DecodeRuneInString(tokText); unicode.IsUpper(r) {
 			return Type
@@ -55,7 +55,7 @@ func TestAsHTML(t *testing.T) {
 
 	for _, test := range tests {
 		name := test.Name()
-		if !strings.Contains(name, *match) {
+		if test.IsDir() || !strings.Contains(name, *match) {
 			continue
 		}
 		if strings.HasSuffix(name, ".html") {
@@ -77,6 +77,11 @@ func TestAsHTML(t *testing.T) {
 
 		got, err = AsHTML(input, OrderedList())
 		testExpected(t, "AsOrderedListHTML", path, name, ".ol.html", got, err)
+
+		if l := LookupFilename(name); l != nil {
+			got, err = AsHTML(input, UseLexer(l))
+			testExpected(t, "AsHTML with lexer", path, name, ".lexer.html", got, err)
+		}
 	}
 
 	if *saveExp {
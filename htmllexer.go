@@ -0,0 +1,264 @@
+package syntaxhighlight
+
+import "strings"
+
+// htmlLexer tokenizes HTML documents or, if xml is set, XML documents. In
+// HTML, the contents of <script> and <style> elements are tokenized by the
+// lexers registered for their types.
+type htmlLexer struct {
+	xml bool
+}
+
+func init() {
+	Register(LexerConfig{
+		Name:      "html",
+		Aliases:   []string{"htm", "xhtml"},
+		Filenames: []string{"*.html", "*.htm", "*.xhtml"},
+		MimeTypes: []string{"text/html", "application/xhtml+xml"},
+	}, htmlLexer{})
+	Register(LexerConfig{
+		Name:      "xml",
+		Aliases:   []string{"svg"},
+		Filenames: []string{"*.xml", "*.xsd", "*.xsl", "*.xslt", "*.svg", "*.rss", "*.atom", "*.plist", "*.wsdl", "*.csproj", "pom.xml"},
+		MimeTypes: []string{"text/xml", "application/xml", "image/svg+xml", "application/rss+xml", "application/atom+xml"},
+	}, htmlLexer{xml: true})
+}
+
+func (l htmlLexer) Tokens(src []byte) ([]Token, error) {
+	s := newLexState(src)
+	for !s.eof() {
+		l.lex(s)
+	}
+	return s.toks, nil
+}
+
+// lex lexes a single token, or a whole tag.
+func (l htmlLexer) lex(s *lexState) {
+	if s.lexWhitespace() {
+		return
+	}
+	switch c := s.peek(0); {
+	case s.hasPrefix("<!--"):
+		s.pos += len("<!--")
+		if s.acceptUntil("-->") {
+			s.pos += len("-->")
+		}
+		s.emit(Comment)
+	case s.hasPrefix("<![CDATA["):
+		s.pos += len("<![CDATA[")
+		s.emit(Tag)
+		found := s.acceptUntil("]]>")
+		s.emit(String)
+		if found {
+			s.pos += len("]]>")
+			s.emit(Tag)
+		}
+	case s.hasPrefix("<!") && isIdentStartByte(s.peek(2)):
+		lexHTMLDeclaration(s)
+	case s.hasPrefix("<?"):
+		s.pos += 2
+		s.emit(Tag)
+		s.acceptWhile(isHTMLNamePart)
+		s.emit(HTMLTag)
+		lexHTMLAttributes(s)
+		if s.hasPrefix("?>") {
+			s.pos += 2
+			s.emit(Tag)
+		}
+	case s.hasPrefix("</") && isHTMLNameStart(s.peek(2)):
+		s.pos += 2
+		s.emit(Tag)
+		s.acceptWhile(isHTMLNamePart)
+		s.emit(HTMLTag)
+		s.lexWhitespace()
+		if s.acceptByte('>') {
+			s.emit(Tag)
+		}
+	case c == '<' && isHTMLNameStart(s.peek(1)):
+		l.lexElement(s)
+	case c == '&' && acceptHTMLEntity(s):
+		s.emit(Literal)
+	default:
+		// Text, including any "<" and "&" that don't start markup.
+		s.pos++
+		for !s.eof() && s.peek(0) != '<' && s.peek(0) != '&' && s.peek(0) != '\n' {
+			s.pos++
+		}
+		s.emit(Plaintext)
+	}
+}
+
+// lexElement lexes the start tag of an element, and, for HTML <script> and
+// <style> elements, their contents. The "<" is at pos.
+func (l htmlLexer) lexElement(s *lexState) {
+	s.pos++
+	s.emit(Tag)
+	s.acceptWhile(isHTMLNamePart)
+	name := s.text()
+	s.emit(HTMLTag)
+	attrs := lexHTMLAttributes(s)
+	switch {
+	case s.hasPrefix("/>"):
+		s.pos += 2
+		s.emit(Tag)
+		return
+	case s.acceptByte('>'):
+		s.emit(Tag)
+	default:
+		return // unterminated tag
+	}
+	if l.xml {
+		return
+	}
+
+	switch name = strings.ToLower(name); name {
+	case "script":
+		typ := attrs["type"]
+		if typ == "" || strings.EqualFold(typ, "module") {
+			typ = "text/javascript"
+		}
+		lexHTMLRawText(s, name, LookupMimeType(typ))
+	case "style":
+		typ := attrs["type"]
+		if typ == "" {
+			typ = "text/css"
+		}
+		lexHTMLRawText(s, name, LookupMimeType(typ))
+	}
+}
+
+// lexHTMLRawText lexes the contents of an element, up to its end tag, with
+// the lexer l. If l is nil, the contents are a single Plaintext token.
+func lexHTMLRawText(s *lexState, name string, l Lexer) {
+	for !s.eof() && !(s.hasPrefixFold("</"+name) && !isHTMLNamePart(rune(s.peek(2+len(name))))) {
+		s.pos++
+	}
+	s.delegate(l, Plaintext)
+}
+
+// lexHTMLAttributes lexes the attributes of a tag, up to (not including) the
+// ">", "/>" or "?>" that ends it, and returns their values by lowercased
+// name.
+func lexHTMLAttributes(s *lexState) map[string]string {
+	attrs := make(map[string]string)
+	name := ""
+	for !s.eof() {
+		if s.lexWhitespace() {
+			continue
+		}
+		switch c := s.peek(0); {
+		case c == '>' || s.hasPrefix("/>") || s.hasPrefix("?>"):
+			return attrs
+		case c == '=':
+			s.pos++
+			s.emit(Punctuation)
+			s.lexWhitespace()
+			switch q := s.peek(0); q {
+			case '"', '\'':
+				s.pos++
+				s.acceptQuoted(string(q), false, true)
+				attrs[name] = strings.Trim(s.text(), string(q))
+			default:
+				for !s.eof() && !isHTMLSpace(s.peek(0)) && s.peek(0) != '>' {
+					s.pos++
+				}
+				attrs[name] = s.text()
+			}
+			s.emit(HTMLAttrValue)
+		case c == '<':
+			return attrs // a tag that was never closed
+		default:
+			for !s.eof() && !isHTMLSpace(s.peek(0)) && !strings.ContainsRune("=>/<", rune(s.peek(0))) {
+				s.pos++
+			}
+			if s.pos == s.start {
+				s.pos++ // a stray "/"
+				s.emit(Punctuation)
+				continue
+			}
+			name = strings.ToLower(s.text())
+			attrs[name] = ""
+			s.emit(HTMLAttrName)
+		}
+	}
+	return attrs
+}
+
+// lexHTMLDeclaration lexes a markup declaration such as <!DOCTYPE html>. The
+// "<!" is at pos.
+func lexHTMLDeclaration(s *lexState) {
+	s.pos += 2
+	s.emit(Tag)
+	s.acceptWhile(isHTMLNamePart)
+	s.emit(Keyword)
+	for !s.eof() {
+		if s.lexWhitespace() {
+			continue
+		}
+		switch c := s.peek(0); {
+		case c == '>':
+			s.pos++
+			s.emit(Tag)
+			return
+		case c == '"' || c == '\'':
+			s.pos++
+			s.acceptQuoted(string(c), false, true)
+			s.emit(String)
+		case c == '[' || c == '<':
+			// The internal subset of a DTD, whose declarations are lexed
+			// as markup of their own.
+			return
+		default:
+			for !s.eof() && !isHTMLSpace(s.peek(0)) && !strings.ContainsRune(">\"'[<", rune(s.peek(0))) {
+				s.pos++
+			}
+			s.emit(Plaintext)
+		}
+	}
+}
+
+// acceptHTMLEntity advances past a character reference such as "&amp;",
+// "&#39;" or "&#x27;", and reports whether there was one at pos.
+func acceptHTMLEntity(s *lexState) bool {
+	i := 1
+	if s.peek(i) == '#' {
+		i++
+		digit := isDigit
+		if s.peek(i) == 'x' || s.peek(i) == 'X' {
+			i++
+			digit = isHexDigit
+		}
+		if !digit(s.peek(i)) {
+			return false
+		}
+		for digit(s.peek(i)) {
+			i++
+		}
+	} else {
+		if !isIdentStartByte(s.peek(i)) {
+			return false
+		}
+		for isIdentStartByte(s.peek(i)) || isDigit(s.peek(i)) {
+			i++
+		}
+	}
+	if s.peek(i) != ';' {
+		return false
+	}
+	s.pos += i + 1
+	return true
+}
+
+func isHTMLSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == '\f'
+}
+
+func isHTMLNameStart(c byte) bool {
+	return isIdentStartByte(c) || c == ':'
+}
+
+// isHTMLNamePart reports whether r may be part of a tag name, such as "h1",
+// "my-element" or "svg:rect".
+func isHTMLNamePart(r rune) bool {
+	return isIdentPart(r) || r == '-' || r == ':' || r == '.'
+}
@@ -0,0 +1,302 @@
+package syntaxhighlight
+
+import "strings"
+
+// jsLexer tokenizes JavaScript and TypeScript source code, optionally with
+// JSX elements.
+type jsLexer struct {
+	typescript bool
+	jsx        bool
+}
+
+func init() {
+	Register(LexerConfig{
+		Name:         "javascript",
+		Aliases:      []string{"js", "jsx", "node"},
+		Filenames:    []string{"*.js", "*.jsx", "*.mjs", "*.cjs"},
+		MimeTypes:    []string{"application/javascript", "text/javascript", "text/jsx"},
+		Interpreters: []string{"node", "nodejs", "deno", "bun"},
+	}, jsLexer{jsx: true})
+	Register(LexerConfig{
+		Name:      "typescript",
+		Aliases:   []string{"ts"},
+		Filenames: []string{"*.ts", "*.mts", "*.cts"},
+		MimeTypes: []string{"application/typescript", "text/typescript"},
+	}, jsLexer{typescript: true})
+	Register(LexerConfig{
+		Name:      "tsx",
+		Filenames: []string{"*.tsx"},
+		MimeTypes: []string{"text/tsx"},
+	}, jsLexer{typescript: true, jsx: true})
+}
+
+var jsOperators = []string{
+	">>>=", "...", "===", "!==", "**=", "<<=", ">>=", ">>>", "&&=", "||=",
+	"??=", "=>", "==", "!=", "<=", ">=", "&&", "||", "??", "?.", "++", "--",
+	"+=", "-=", "*=", "/=", "%=", "&=", "|=", "^=", "**", "<<", ">>",
+}
+
+func (l jsLexer) Tokens(src []byte) ([]Token, error) {
+	s := newLexState(src)
+	if s.hasPrefix("#!") {
+		s.acceptLine()
+		s.emit(Comment)
+	}
+	for !s.eof() {
+		l.lex(s)
+	}
+	return s.toks, nil
+}
+
+func (l jsLexer) keywordTable() *keywordTable {
+	if l.typescript {
+		return typescriptKeywords
+	}
+	return javascriptKeywords
+}
+
+// lex lexes a single token.
+func (l jsLexer) lex(s *lexState) {
+	if s.lexWhitespace() {
+		return
+	}
+	c := s.peek(0)
+	switch {
+	case s.hasPrefix("//"):
+		s.acceptLine()
+		s.emit(Comment)
+	case s.hasPrefix("/*"):
+		if s.acceptUntil("*/") {
+			s.pos += 2
+		}
+		s.emit(Comment)
+	case c == '"' || c == '\'':
+		s.pos++
+		s.acceptQuoted(string(c), true, false)
+		s.emit(String)
+	case c == '`':
+		l.lexTemplate(s)
+	case c == '/' && jsExpectsOperand(s):
+		lexJSRegexp(s)
+	case c == '<' && l.jsx && jsExpectsOperand(s) && (isIdentStartByte(s.peek(1)) || s.peek(1) == '>'):
+		l.lexJSXElement(s)
+	case c == '@' && (isIdentStartByte(s.peek(1)) || s.peek(1) == '$'):
+		s.pos++
+		s.acceptWhile(isJSIdentPart)
+		s.emit(Decorator)
+	case c == '#' && isIdentStartByte(s.peek(1)):
+		s.pos++
+		s.acceptWhile(isJSIdentPart)
+		s.emit(Field) // a private class member
+	case s.acceptNumber('_'):
+		s.emit(Decimal)
+	case isIdentStartByte(c) || c == '$' || c >= 0x80:
+		r, w := s.peekRune()
+		if !isJSIdentPart(r) {
+			s.pos += w
+			s.emit(Punctuation)
+			return
+		}
+		s.acceptWhile(isJSIdentPart)
+		s.emit(l.identKind(s))
+	case s.acceptAny(jsOperators):
+		s.emit(Punctuation)
+	default:
+		_, w := s.peekRune()
+		s.pos += w
+		s.emit(Punctuation)
+	}
+}
+
+func isJSIdentPart(r rune) bool {
+	return r == '$' || isIdentPart(r)
+}
+
+// identKind returns the kind of the identifier that is the current token.
+func (l jsLexer) identKind(s *lexState) Kind {
+	ident := s.text()
+	prev, _ := s.last()
+	if prev.Text == "." || prev.Text == "?." {
+		return identKindByCase(ident)
+	}
+	if kind, ok := l.keywordTable().kind(ident); ok {
+		return kind
+	}
+	if prev.Kind == Keyword {
+		switch prev.Text {
+		case "function":
+			return Function
+		case "class", "interface", "type", "enum", "extends", "implements", "new":
+			return Type
+		}
+	}
+	return identKindByCase(ident)
+}
+
+// jsExpectsOperand reports whether an operand rather than an operator may
+// start at the current token, which tells a regexp from a division and a JSX
+// element from a less-than comparison.
+func jsExpectsOperand(s *lexState) bool {
+	prev, ok := s.last()
+	if !ok {
+		return true
+	}
+	switch prev.Kind {
+	case Punctuation:
+		switch prev.Text {
+		case ")", "]", "}", "++", "--":
+			return false
+		}
+		return true
+	case Keyword:
+		return prev.Text != "this" && prev.Text != "super"
+	}
+	return false
+}
+
+// lexJSRegexp lexes a regular expression literal, which starts at pos.
+func lexJSRegexp(s *lexState) {
+	s.pos++
+	inClass := false
+loop:
+	for !s.eof() {
+		switch s.peek(0) {
+		case '\\':
+			if s.pos+1 < len(s.src) && s.src[s.pos+1] != '\n' {
+				s.pos++ // the escaped byte; a newline still ends the regexp
+			}
+		case '[':
+			inClass = true
+		case ']':
+			inClass = false
+		case '/':
+			if !inClass {
+				s.pos++
+				break loop
+			}
+		case '\n':
+			break loop
+		}
+		s.pos++
+	}
+	s.acceptWhile(isIdentPart) // flags
+	s.emit(String)
+}
+
+// lexTemplate lexes a template literal, whose ${} substitutions are
+// highlighted as code. The opening backquote is at pos.
+func (l jsLexer) lexTemplate(s *lexState) {
+	s.pos++
+	for !s.eof() {
+		switch {
+		case s.peek(0) == '`':
+			s.pos++
+			s.emit(String)
+			return
+		case s.peek(0) == '\\' && s.pos+1 < len(s.src):
+			s.pos += 2
+		case s.hasPrefix("${"):
+			s.emit(String)
+			s.pos += 2
+			s.emit(Punctuation)
+			l.lexEmbedded(s)
+		default:
+			s.pos++
+		}
+	}
+	s.emit(String)
+}
+
+// lexEmbedded lexes code embedded in a template literal or JSX, up to and
+// including the closing brace that balances an already consumed "{".
+func (l jsLexer) lexEmbedded(s *lexState) {
+	s.delegateBalanced('{', '}', "\"'`", l, Plaintext)
+	if s.acceptByte('}') {
+		s.emit(Punctuation)
+	}
+}
+
+// lexJSXElement lexes a JSX element, including its children and closing
+// tag. The "<" of its opening tag is at pos.
+func (l jsLexer) lexJSXElement(s *lexState) {
+	if selfClosing := l.lexJSXTag(s); selfClosing {
+		return
+	}
+	for !s.eof() {
+		switch {
+		case s.hasPrefix("</"):
+			s.pos += 2
+			s.emit(Tag)
+			s.acceptWhile(isJSXNamePart)
+			s.emit(HTMLTag)
+			s.lexWhitespace()
+			if s.acceptByte('>') {
+				s.emit(Tag)
+			}
+			return
+		case s.peek(0) == '<':
+			l.lexJSXElement(s)
+		case s.peek(0) == '{':
+			s.pos++
+			s.emit(Punctuation)
+			l.lexEmbedded(s)
+		default:
+			for !s.eof() && s.peek(0) != '<' && s.peek(0) != '{' {
+				s.pos++
+			}
+			s.emit(Plaintext)
+		}
+	}
+}
+
+// lexJSXTag lexes the opening tag of a JSX element, and reports whether it
+// is self-closing.
+func (l jsLexer) lexJSXTag(s *lexState) (selfClosing bool) {
+	s.pos++
+	s.emit(Tag)
+	s.acceptWhile(isJSXNamePart)
+	s.emit(HTMLTag)
+	for !s.eof() {
+		if s.lexWhitespace() {
+			continue
+		}
+		c := s.peek(0)
+		r, w := s.peekRune()
+		switch {
+		case c == '>':
+			s.pos++
+			s.emit(Tag)
+			return false
+		case s.hasPrefix("/>"):
+			s.pos += 2
+			s.emit(Tag)
+			return true
+		case c == '=':
+			s.pos++
+			s.emit(Punctuation)
+		case c == '"' || c == '\'':
+			s.pos++
+			s.acceptQuoted(string(c), false, true)
+			s.emit(HTMLAttrValue)
+		case c == '{':
+			s.pos++
+			s.emit(Punctuation)
+			l.lexEmbedded(s)
+		case isJSXNamePart(r):
+			s.acceptWhile(isJSXNamePart)
+			s.emit(HTMLAttrName)
+		default:
+			// Not valid JSX; give up on the tag.
+			s.pos += w
+			s.emit(Punctuation)
+			return true
+		}
+	}
+	return true
+}
+
+// isJSXNamePart reports whether r may be part of a JSX element or attribute
+// name, such as "my-element", "svg:rect" or "Foo.Bar".
+func isJSXNamePart(r rune) bool {
+	return isJSIdentPart(r) || strings.ContainsRune("-:.", r)
+}
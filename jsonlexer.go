@@ -0,0 +1,91 @@
+package syntaxhighlight
+
+// jsonLexer tokenizes JSON documents. Object keys are highlighted as Field,
+// to tell them from string values. Comments, as allowed by JSONC, are
+// recognized too.
+type jsonLexer struct{}
+
+func init() {
+	Register(LexerConfig{
+		Name:      "json",
+		Filenames: []string{"*.json", "*.geojson", "*.webmanifest", "*.ipynb", ".babelrc", ".prettierrc"},
+		MimeTypes: []string{"application/json", "application/ld+json", "application/manifest+json"},
+	}, jsonLexer{})
+	Register(LexerConfig{
+		Name:      "jsonc",
+		Aliases:   []string{"json5"},
+		Filenames: []string{"*.jsonc", "*.json5", "tsconfig.json", "jsconfig.json", ".eslintrc.json", "devcontainer.json"},
+		MimeTypes: []string{"application/jsonc"},
+	}, jsonLexer{})
+}
+
+func (jsonLexer) Tokens(src []byte) ([]Token, error) {
+	s := newLexState(src)
+	for !s.eof() {
+		lexJSON(s)
+	}
+	return s.toks, nil
+}
+
+func (jsonLexer) keywordTable() *keywordTable { return jsonKeywords }
+
+// lexJSON lexes a single JSON token.
+func lexJSON(s *lexState) {
+	if s.lexWhitespace() {
+		return
+	}
+	c := s.peek(0)
+	switch {
+	case s.hasPrefix("//"):
+		s.acceptLine()
+		s.emit(Comment)
+	case s.hasPrefix("/*"):
+		if s.acceptUntil("*/") {
+			s.pos += 2
+		}
+		s.emit(Comment)
+	case c == '"' || c == '\'':
+		s.pos++
+		s.acceptQuoted(string(c), true, false)
+		if jsonIsKey(s) {
+			s.emit(Field)
+		} else {
+			s.emit(String)
+		}
+	case (c == '-' || c == '+') && (isDigit(s.peek(1)) || s.peek(1) == '.'):
+		s.pos++
+		s.acceptNumber(0)
+		s.emit(Decimal)
+	case s.acceptNumber(0):
+		s.emit(Decimal)
+	case isIdentStartByte(c) || c == '$':
+		s.acceptWhile(func(r rune) bool { return isIdentPart(r) || r == '$' })
+		switch kind, ok := jsonKeywords.kind(s.text()); {
+		case jsonIsKey(s):
+			s.emit(Field) // an unquoted key, as allowed by JSON5
+		case ok:
+			s.emit(kind)
+		default:
+			s.emit(Plaintext)
+		}
+	default:
+		_, w := s.peekRune()
+		s.pos += w
+		s.emit(Punctuation)
+	}
+}
+
+// jsonIsKey reports whether the current token is followed by a colon, which
+// makes it an object key.
+func jsonIsKey(s *lexState) bool {
+	for i := s.pos; i < len(s.src); i++ {
+		switch s.src[i] {
+		case ' ', '\t', '\r', '\n':
+			continue
+		case ':':
+			return true
+		}
+		return false
+	}
+	return false
+}
@@ -1,5 +1,10 @@
 package syntaxhighlight
 
+import "strings"
+
+// keywords is the merged set of keywords of JavaScript, Python, Ruby, C++, Go
+// and others. It is only used by FallbackLexer, when the language is unknown;
+// lexers for a specific language use a keywordTable of their own.
 var keywords = map[string]struct{}{
 	"BEGIN":            {},
 	"END":              {},
@@ -175,3 +180,559 @@ var keywords = map[string]struct{}{
 	"with":             {},
 	"yield":            {},
 }
+
+// keywordTable holds the reserved and predeclared words of a language, along
+// with the kind each of them is highlighted as.
+type keywordTable struct {
+	keywords map[string]struct{} // highlighted as Keyword
+	types    map[string]struct{} // builtin types, highlighted as Type
+	builtins map[string]struct{} // builtin functions and names, highlighted as Builtin
+	literals map[string]struct{} // predeclared constants, highlighted as Literal
+	fold     bool                // whether words are case-insensitive, and so listed in lower case
+}
+
+// kind returns the kind of the identifier ident in the table's language, and
+// whether the table contains ident at all.
+func (t *keywordTable) kind(ident string) (Kind, bool) {
+	if t.fold {
+		ident = strings.ToLower(ident)
+	}
+	if _, ok := t.keywords[ident]; ok {
+		return Keyword, true
+	}
+	if _, ok := t.types[ident]; ok {
+		return Type, true
+	}
+	if _, ok := t.builtins[ident]; ok {
+		return Builtin, true
+	}
+	if _, ok := t.literals[ident]; ok {
+		return Literal, true
+	}
+	return 0, false
+}
+
+func wordSet(words ...string) map[string]struct{} {
+	set := make(map[string]struct{}, len(words))
+	for _, w := range words {
+		set[w] = struct{}{}
+	}
+	return set
+}
+
+// union returns a new set with the words of all the given sets.
+func union(sets ...map[string]struct{}) map[string]struct{} {
+	u := make(map[string]struct{})
+	for _, set := range sets {
+		for w := range set {
+			u[w] = struct{}{}
+		}
+	}
+	return u
+}
+
+// fallbackKeywords is the merged table used by FallbackLexer when the
+// language is unknown.
+var fallbackKeywords = &keywordTable{keywords: keywords}
+
+var goKeywords = &keywordTable{
+	keywords: wordSet(
+		"break", "case", "chan", "const", "continue", "default", "defer",
+		"else", "fallthrough", "for", "func", "go", "goto", "if", "import",
+		"interface", "map", "package", "range", "return", "select", "struct",
+		"switch", "type", "var",
+	),
+	types: wordSet(
+		"any", "bool", "byte", "comparable", "complex64", "complex128",
+		"error", "float32", "float64", "int", "int8", "int16", "int32",
+		"int64", "rune", "string", "uint", "uint8", "uint16", "uint32",
+		"uint64", "uintptr",
+	),
+	builtins: wordSet(
+		"append", "cap", "clear", "close", "complex", "copy", "delete",
+		"imag", "len", "make", "max", "min", "new", "panic", "print",
+		"println", "real", "recover",
+	),
+	literals: wordSet("true", "false", "nil", "iota"),
+}
+
+var pythonKeywords = &keywordTable{
+	keywords: wordSet(
+		"and", "as", "assert", "async", "await", "break", "class",
+		"continue", "def", "del", "elif", "else", "except", "finally", "for",
+		"from", "global", "if", "import", "in", "is", "lambda", "nonlocal",
+		"not", "or", "pass", "raise", "return", "try", "while", "with",
+		"yield",
+	),
+	types: wordSet(
+		"bool", "bytearray", "bytes", "complex", "dict", "float",
+		"frozenset", "int", "list", "object", "set", "str", "tuple", "type",
+	),
+	builtins: wordSet(
+		"abs", "all", "any", "ascii", "bin", "callable", "chr",
+		"classmethod", "compile", "delattr", "dir", "divmod", "enumerate",
+		"eval", "exec", "filter", "format", "getattr", "globals", "hasattr",
+		"hash", "help", "hex", "id", "input", "isinstance", "issubclass",
+		"iter", "len", "locals", "map", "max", "min", "next", "oct", "open",
+		"ord", "pow", "print", "property", "range", "repr", "reversed",
+		"round", "setattr", "slice", "sorted", "staticmethod", "sum",
+		"super", "vars", "zip", "__import__", "self", "cls",
+	),
+	literals: wordSet("True", "False", "None", "NotImplemented", "Ellipsis", "__debug__"),
+}
+
+var rubyKeywords = &keywordTable{
+	keywords: wordSet(
+		"BEGIN", "END", "alias", "and", "begin", "break", "case", "class",
+		"def", "defined?", "do", "else", "elsif", "end", "ensure", "for",
+		"if", "in", "module", "next", "not", "or", "redo", "rescue", "retry",
+		"return", "self", "super", "then", "undef", "unless", "until",
+		"when", "while", "yield", "__FILE__", "__LINE__", "__method__",
+		"__dir__", "__ENCODING__",
+	),
+	builtins: wordSet(
+		"attr_accessor", "attr_reader", "attr_writer", "caller", "catch",
+		"extend", "include", "lambda", "loop", "module_function", "p",
+		"prepend", "print", "private", "proc", "protected", "public", "puts",
+		"raise", "require", "require_relative", "throw",
+	),
+	literals: wordSet("true", "false", "nil"),
+}
+
+var javascriptKeywords = &keywordTable{
+	keywords: wordSet(
+		"async", "await", "break", "case", "catch", "class", "const",
+		"continue", "debugger", "default", "delete", "do", "else", "export",
+		"extends", "finally", "for", "from", "function", "get", "if",
+		"import", "in", "instanceof", "let", "new", "of", "return", "set",
+		"static", "super", "switch", "this", "throw", "try", "typeof", "var",
+		"void", "while", "with", "yield",
+	),
+	builtins: wordSet(
+		"arguments", "clearInterval", "clearTimeout", "console", "document",
+		"exports", "globalThis", "isFinite", "isNaN", "module", "parseFloat",
+		"parseInt", "require", "setInterval", "setTimeout", "window",
+	),
+	literals: wordSet("true", "false", "null", "undefined", "NaN", "Infinity"),
+}
+
+// typescriptKeywords extends javascriptKeywords with the keywords and
+// builtin types of TypeScript.
+var typescriptKeywords = &keywordTable{
+	keywords: union(javascriptKeywords.keywords, wordSet(
+		"abstract", "as", "asserts", "declare", "enum", "implements",
+		"infer", "interface", "is", "keyof", "namespace", "override",
+		"private", "protected", "public", "readonly", "satisfies", "type",
+		"unique",
+	)),
+	types: wordSet(
+		"any", "bigint", "boolean", "never", "number", "object", "string",
+		"symbol", "unknown",
+	),
+	builtins: javascriptKeywords.builtins,
+	literals: javascriptKeywords.literals,
+}
+
+var javaKeywords = &keywordTable{
+	keywords: wordSet(
+		"abstract", "assert", "break", "case", "catch", "class", "const",
+		"continue", "default", "do", "else", "enum", "extends", "final",
+		"finally", "for", "goto", "if", "implements", "import", "instanceof",
+		"interface", "native", "new", "non-sealed", "package", "permits",
+		"private", "protected", "public", "record", "return", "sealed",
+		"static", "strictfp", "super", "switch", "synchronized", "this",
+		"throw", "throws", "transient", "try", "var", "void", "volatile",
+		"while", "yield",
+	),
+	types:    wordSet("boolean", "byte", "char", "double", "float", "int", "long", "short"),
+	literals: wordSet("true", "false", "null"),
+}
+
+var cKeywords = &keywordTable{
+	keywords: wordSet(
+		"auto", "break", "case", "const", "continue", "default", "do",
+		"else", "enum", "extern", "for", "goto", "if", "inline", "register",
+		"restrict", "return", "signed", "sizeof", "static", "struct",
+		"switch", "typedef", "union", "unsigned", "volatile", "while",
+		"_Alignas", "_Alignof", "_Atomic", "_Generic", "_Noreturn",
+		"_Static_assert", "_Thread_local",
+	),
+	types: wordSet(
+		"_Bool", "_Complex", "_Imaginary", "bool", "char", "double", "float",
+		"int", "long", "short", "void", "FILE", "size_t", "ssize_t",
+		"ptrdiff_t", "intptr_t", "uintptr_t", "int8_t", "int16_t",
+		"int32_t", "int64_t", "uint8_t", "uint16_t", "uint32_t", "uint64_t",
+	),
+	literals: wordSet("NULL", "true", "false"),
+}
+
+// cppKeywords extends cKeywords with the keywords and types of C++.
+var cppKeywords = &keywordTable{
+	keywords: union(cKeywords.keywords, wordSet(
+		"alignas", "alignof", "and", "and_eq", "asm", "bitand", "bitor",
+		"catch", "class", "co_await", "co_return", "co_yield", "compl",
+		"concept", "const_cast", "consteval", "constexpr", "constinit",
+		"decltype", "delete", "dynamic_cast", "explicit", "export", "friend",
+		"mutable", "namespace", "new", "noexcept", "not", "not_eq",
+		"operator", "or", "or_eq", "private", "protected", "public",
+		"reinterpret_cast", "requires", "static_assert", "static_cast",
+		"template", "this", "thread_local", "throw", "try", "typeid",
+		"typename", "using", "virtual", "xor", "xor_eq",
+	)),
+	types:    union(cKeywords.types, wordSet("char8_t", "char16_t", "char32_t", "wchar_t")),
+	literals: wordSet("NULL", "nullptr", "true", "false"),
+}
+
+var rustKeywords = &keywordTable{
+	keywords: wordSet(
+		"as", "async", "await", "break", "const", "continue", "crate", "dyn",
+		"else", "enum", "extern", "fn", "for", "if", "impl", "in", "let",
+		"loop", "match", "mod", "move", "mut", "pub", "ref", "return", "self",
+		"static", "struct", "super", "trait", "type", "union", "unsafe",
+		"use", "where", "while", "yield",
+	),
+	types: wordSet(
+		"Self", "bool", "char", "str", "i8", "i16", "i32", "i64", "i128",
+		"isize", "u8", "u16", "u32", "u64", "u128", "usize", "f32", "f64",
+		"Box", "Option", "Result", "String", "Vec",
+	),
+	builtins: wordSet("Some", "None", "Ok", "Err"),
+	literals: wordSet("true", "false"),
+}
+
+var shellKeywords = &keywordTable{
+	keywords: wordSet(
+		"if", "then", "else", "elif", "fi", "case", "esac", "for", "select",
+		"while", "until", "do", "done", "in", "function", "time", "coproc",
+		"[[", "]]", "!",
+	),
+	builtins: wordSet(
+		"alias", "bg", "bind", "break", "builtin", "caller", "cd", "command",
+		"compgen", "complete", "continue", "declare", "dirs", "disown",
+		"echo", "enable", "eval", "exec", "exit", "export", "false", "fc",
+		"fg", "getopts", "hash", "help", "history", "jobs", "kill", "let",
+		"local", "logout", "mapfile", "popd", "printf", "pushd", "pwd",
+		"read", "readarray", "readonly", "return", "set", "shift", "shopt",
+		"source", "suspend", "test", "times", "trap", "true", "type",
+		"typeset", "ulimit", "umask", "unalias", "unset", "wait",
+	),
+}
+
+// sqlKeywords holds the keywords of standard SQL, in lower case; SQL
+// keywords are case-insensitive. The tables of SQL dialects extend it.
+var sqlKeywords = &keywordTable{
+	fold: true,
+	keywords: wordSet(
+		"add", "all", "alter", "and", "any", "as", "asc", "begin", "between",
+		"by", "cascade", "case", "check", "column", "commit", "constraint",
+		"create", "cross", "current_date", "current_time",
+		"current_timestamp", "database", "default", "delete", "desc",
+		"distinct", "drop", "else", "end", "escape", "except", "exists",
+		"fetch", "first", "following", "for", "foreign", "from", "full",
+		"function", "grant", "group", "having", "if", "in", "index", "inner",
+		"insert", "intersect", "into", "is", "join", "key", "last", "left",
+		"like", "limit", "natural", "not", "nulls", "of", "offset", "on",
+		"or", "order", "outer", "over", "partition", "preceding", "primary",
+		"procedure", "references", "replace", "revoke", "right", "rollback",
+		"row", "rows", "schema", "select", "set", "table", "then", "to",
+		"transaction", "trigger", "truncate", "unbounded", "union", "unique",
+		"update", "using", "values", "view", "when", "where", "window",
+		"with",
+	),
+	types: wordSet(
+		"bigint", "binary", "bit", "blob", "boolean", "char", "character",
+		"clob", "date", "decimal", "double", "float", "int", "integer",
+		"interval", "numeric", "precision", "real", "smallint", "text",
+		"time", "timestamp", "varbinary", "varchar", "varying", "zone",
+	),
+	builtins: wordSet(
+		"abs", "avg", "cast", "coalesce", "count", "dense_rank", "extract",
+		"lag", "lead", "length", "lower", "max", "min", "nullif", "rank",
+		"round", "row_number", "substring", "sum", "trim", "upper",
+	),
+	literals: wordSet("null", "true", "false", "unknown"),
+}
+
+var postgresKeywords = &keywordTable{
+	fold: true,
+	keywords: union(sqlKeywords.keywords, wordSet(
+		"analyze", "concurrently", "conflict", "declare", "do", "extension",
+		"ilike", "language", "lateral", "materialized", "nothing", "notify",
+		"listen", "owner", "perform", "plpgsql", "raise", "returning",
+		"returns", "sequence", "similar", "tablespace", "temp", "temporary",
+		"type", "unlogged", "vacuum", "volatile", "immutable", "stable",
+	)),
+	types: union(sqlKeywords.types, wordSet(
+		"bigserial", "bool", "bytea", "cidr", "float4", "float8", "inet",
+		"int2", "int4", "int8", "json", "jsonb", "money", "serial",
+		"smallserial", "timestamptz", "timetz", "tsquery", "tsvector",
+		"uuid", "xml",
+	)),
+	builtins: union(sqlKeywords.builtins, wordSet(
+		"array_agg", "generate_series", "jsonb_build_object", "now",
+		"string_agg", "to_char", "to_timestamp", "unnest",
+	)),
+	literals: sqlKeywords.literals,
+}
+
+var mysqlKeywords = &keywordTable{
+	fold: true,
+	keywords: union(sqlKeywords.keywords, wordSet(
+		"auto_increment", "charset", "collate", "delimiter", "describe",
+		"duplicate", "engine", "explain", "ignore", "regexp", "rlike", "show",
+		"straight_join", "unsigned", "use", "zerofill",
+	)),
+	types: union(sqlKeywords.types, wordSet(
+		"datetime", "enum", "longblob", "longtext", "mediumblob",
+		"mediumint", "mediumtext", "tinyblob", "tinyint", "tinytext", "year",
+	)),
+	builtins: union(sqlKeywords.builtins, wordSet(
+		"concat", "date_format", "group_concat", "ifnull", "last_insert_id",
+		"now", "unix_timestamp",
+	)),
+	literals: sqlKeywords.literals,
+}
+
+var sqliteKeywords = &keywordTable{
+	fold: true,
+	keywords: union(sqlKeywords.keywords, wordSet(
+		"abort", "attach", "autoincrement", "conflict", "detach", "explain",
+		"fail", "glob", "ignore", "indexed", "instead", "pragma", "raise",
+		"regexp", "reindex", "returning", "rowid", "strict", "temp",
+		"temporary", "vacuum", "virtual", "without",
+	)),
+	types: sqlKeywords.types,
+	builtins: union(sqlKeywords.builtins, wordSet(
+		"date", "datetime", "group_concat", "ifnull", "instr", "json_extract",
+		"julianday", "last_insert_rowid", "printf", "random", "strftime",
+		"typeof",
+	)),
+	literals: sqlKeywords.literals,
+}
+
+var protobufKeywords = &keywordTable{
+	keywords: wordSet(
+		"syntax", "edition", "package", "import", "public", "weak", "option",
+		"message", "enum", "service", "rpc", "returns", "stream", "oneof",
+		"map", "extend", "extensions", "reserved", "to", "max", "repeated",
+		"optional", "required", "group",
+	),
+	types: wordSet(
+		"double", "float", "int32", "int64", "uint32", "uint64", "sint32",
+		"sint64", "fixed32", "fixed64", "sfixed32", "sfixed64", "bool",
+		"string", "bytes",
+	),
+	literals: wordSet("true", "false", "inf", "nan"),
+}
+
+var graphqlKeywords = &keywordTable{
+	keywords: wordSet(
+		"query", "mutation", "subscription", "fragment", "on", "type",
+		"interface", "union", "enum", "input", "scalar", "schema", "extend",
+		"implements", "directive", "repeatable",
+	),
+	types:    wordSet("Int", "Float", "String", "Boolean", "ID"),
+	literals: wordSet("true", "false", "null"),
+}
+
+// phpKeywords holds the keywords of PHP, in lower case; like the names of
+// functions and classes, PHP keywords are case-insensitive.
+var phpKeywords = &keywordTable{
+	fold: true,
+	keywords: wordSet(
+		"abstract", "and", "as", "break", "callable", "case", "catch", "class",
+		"clone", "const", "continue", "declare", "default", "do", "echo",
+		"else", "elseif", "enddeclare", "endfor", "endforeach", "endif",
+		"endswitch", "endwhile", "enum", "extends", "final", "finally", "fn",
+		"for", "foreach", "function", "global", "goto", "if", "implements",
+		"include", "include_once", "instanceof", "insteadof", "interface",
+		"match", "namespace", "new", "or", "print", "private", "protected",
+		"public", "readonly", "require", "require_once", "return", "static",
+		"switch", "throw", "trait", "try", "use", "var", "while", "xor",
+		"yield",
+	),
+	types: wordSet(
+		"array", "bool", "float", "int", "iterable", "mixed", "never",
+		"object", "string", "void", "self", "parent",
+	),
+	builtins: wordSet(
+		"die", "empty", "eval", "exit", "isset", "list", "unset",
+		"__class__", "__dir__", "__file__", "__function__", "__line__",
+		"__method__", "__namespace__", "__trait__",
+	),
+	literals: wordSet("true", "false", "null"),
+}
+
+var clojureKeywords = &keywordTable{
+	keywords: wordSet(
+		"def", "defn", "defn-", "defmacro", "defmulti", "defmethod",
+		"defprotocol", "defrecord", "deftype", "defonce", "definterface",
+		"fn", "fn*", "if", "if-not", "if-let", "if-some", "when", "when-not",
+		"when-let", "when-some", "when-first", "cond", "condp", "cond->",
+		"cond->>", "case", "do", "let", "letfn", "loop", "recur", "binding",
+		"try", "catch", "finally", "throw", "quote", "var", "new", "set!",
+		"ns", "import", "require", "use", "->", "->>", "some->", "some->>",
+		"as->", "doto", "doseq", "dotimes", "for", "while", "and", "or",
+		"reify", "proxy", "extend-type", "extend-protocol", "delay",
+		"lazy-seq", "declare", "&",
+	),
+	builtins: wordSet(
+		"map", "filter", "remove", "reduce", "first", "rest", "next", "cons",
+		"conj", "assoc", "dissoc", "get", "get-in", "update", "update-in",
+		"seq", "vec", "vector", "list", "hash-map", "hash-set", "count",
+		"str", "println", "prn", "print", "apply", "partial", "comp",
+		"identity", "inc", "dec", "+", "-", "*", "/", "=", "==", "<", ">",
+		"<=", ">=", "not", "not=", "nil?", "some?", "empty?", "atom", "swap!",
+		"reset!", "deref", "keyword", "symbol", "name", "range", "take",
+		"drop", "into", "merge", "keys", "vals",
+	),
+	literals: wordSet("nil", "true", "false"),
+}
+
+var schemeKeywords = &keywordTable{
+	keywords: wordSet(
+		"define", "define-syntax", "define-record-type", "define-values",
+		"define-library", "lambda", "case-lambda", "let", "let*", "letrec",
+		"letrec*", "let-values", "let*-values", "let-syntax",
+		"letrec-syntax", "if", "cond", "case", "when", "unless", "and", "or",
+		"begin", "do", "delay", "delay-force", "quote", "quasiquote",
+		"unquote", "unquote-splicing", "set!", "syntax-rules", "syntax-case",
+		"else", "=>", "import", "export", "library", "guard", "parameterize",
+		"include",
+	),
+	builtins: wordSet(
+		"car", "cdr", "cons", "list", "append", "map", "for-each", "filter",
+		"reduce", "apply", "length", "reverse", "vector", "vector-ref",
+		"vector-set!", "null?", "pair?", "list?", "eq?", "eqv?", "equal?",
+		"not", "display", "newline", "write", "+", "-", "*", "/", "=", "<",
+		">", "<=", ">=", "string-append", "number->string",
+		"string->symbol", "symbol->string", "error", "call/cc",
+		"call-with-current-continuation", "values", "call-with-values",
+		"assoc", "assq", "member", "memq",
+	),
+}
+
+var emacsLispKeywords = &keywordTable{
+	keywords: wordSet(
+		"defun", "defmacro", "defvar", "defcustom", "defconst", "defgroup",
+		"defface", "defsubst", "defalias", "define-minor-mode",
+		"define-derived-mode", "lambda", "let", "let*", "if", "when",
+		"unless", "cond", "and", "or", "progn", "prog1", "prog2", "while",
+		"dolist", "dotimes", "catch", "throw", "unwind-protect",
+		"condition-case", "save-excursion", "save-restriction",
+		"save-match-data", "setq", "setq-default", "setq-local",
+		"interactive", "quote", "function", "pcase", "pcase-let", "cl-defun",
+		"cl-loop", "cl-case", "with-current-buffer", "with-temp-buffer",
+		"ignore-errors", "declare", "provide", "require",
+		"eval-when-compile", "eval-and-compile", "&optional", "&rest",
+	),
+	builtins: wordSet(
+		"car", "cdr", "cons", "list", "append", "mapcar", "mapc", "funcall",
+		"apply", "message", "format", "concat", "length", "nth", "nthcdr",
+		"assoc", "assq", "member", "memq", "eq", "equal", "null", "not", "+",
+		"-", "*", "/", "=", "<", ">", "<=", ">=", "1+", "1-", "insert",
+		"buffer-substring", "point", "goto-char", "current-buffer",
+		"set-buffer", "error", "signal", "add-hook", "remove-hook",
+		"global-set-key", "define-key", "kbd",
+	),
+	literals: wordSet("nil", "t"),
+}
+
+// dockerfileKeywords holds the instructions of Dockerfiles, which are
+// case-insensitive but by convention written in upper case; the lexer
+// upper-cases them before looking them up.
+var dockerfileKeywords = &keywordTable{
+	keywords: wordSet(
+		"FROM", "RUN", "CMD", "LABEL", "MAINTAINER", "EXPOSE", "ENV", "ADD",
+		"COPY", "ENTRYPOINT", "VOLUME", "USER", "WORKDIR", "ARG", "ONBUILD",
+		"STOPSIGNAL", "HEALTHCHECK", "SHELL",
+	),
+}
+
+// makefileKeywords holds the directives of GNU make, and its functions and
+// special targets as builtins.
+var makefileKeywords = &keywordTable{
+	keywords: wordSet(
+		"include", "-include", "sinclude", "define", "endef", "ifdef", "ifndef",
+		"ifeq", "ifneq", "else", "endif", "export", "unexport", "override",
+		"private", "undefine", "vpath",
+	),
+	builtins: wordSet(
+		"subst", "patsubst", "strip", "findstring", "filter", "filter-out",
+		"sort", "word", "words", "wordlist", "firstword", "lastword", "dir",
+		"notdir", "suffix", "basename", "addsuffix", "addprefix", "join",
+		"wildcard", "realpath", "abspath", "if", "or", "and", "intcmp",
+		"foreach", "file", "call", "value", "eval", "origin", "flavor", "shell",
+		"error", "warning", "info", "let",
+		".PHONY", ".SUFFIXES", ".DEFAULT", ".PRECIOUS", ".INTERMEDIATE",
+		".NOTINTERMEDIATE", ".SECONDARY", ".SECONDEXPANSION",
+		".DELETE_ON_ERROR", ".IGNORE", ".LOW_RESOLUTION_TIME", ".SILENT",
+		".EXPORT_ALL_VARIABLES", ".NOTPARALLEL", ".ONESHELL", ".POSIX",
+	),
+}
+
+// goAsmKeywords holds the pseudo-instructions of Go assembly, which the
+// assembly lexer highlights as Preprocessor, and the symbol attributes
+// defined in textflag.h, which it highlights as Constant.
+var goAsmKeywords = &keywordTable{
+	keywords: wordSet(
+		"TEXT", "DATA", "GLOBL", "FUNCDATA", "PCDATA", "PCALIGN", "BYTE",
+		"WORD", "LONG", "QUAD",
+	),
+	literals: wordSet(
+		"NOPROF", "DUPOK", "NOSPLIT", "RODATA", "NOPTR", "WRAPPER",
+		"NEEDCTXT", "TLSBSS", "NOFRAME", "REFLECTMETHOD", "TOPFRAME",
+		"ABIWRAPPER", "ABIInternal", "ABI0",
+	),
+}
+
+// nasmKeywords holds the directives and pseudo-instructions of NASM, which
+// the assembly lexer highlights as Preprocessor, and the size specifiers of
+// operands, in lower case.
+var nasmKeywords = &keywordTable{
+	fold: true,
+	keywords: wordSet(
+		"section", "segment", "global", "extern", "common", "bits", "default",
+		"org", "align", "alignb", "cpu", "struc", "endstruc", "istruc", "iend",
+		"at", "db", "dw", "dd", "dq", "dt", "do", "dy", "dz", "resb", "resw",
+		"resd", "resq", "rest", "reso", "resy", "resz", "incbin", "equ",
+		"times",
+	),
+	types: wordSet(
+		"byte", "word", "dword", "qword", "tword", "oword", "yword", "zword",
+		"ptr", "near", "far", "short", "rel", "abs",
+	),
+}
+
+// hclKeywords holds the keywords of HCL expressions and templates, the type
+// constraints of Terraform, as in list(string), and, as builtins, the names
+// that references to values in Terraform start with, as in var.region.
+var hclKeywords = &keywordTable{
+	keywords: wordSet("for", "in", "if", "else", "endif", "endfor"),
+	types:    wordSet("string", "number", "bool", "any", "list", "map", "set", "object", "tuple"),
+	builtins: wordSet("var", "local", "module", "data", "each", "count", "self", "path", "terraform"),
+	literals: wordSet("true", "false", "null"),
+}
+
+// goModKeywords holds the directives of go.mod and go.work files.
+var goModKeywords = &keywordTable{
+	keywords: wordSet(
+		"module", "go", "toolchain", "godebug", "require", "replace",
+		"exclude", "retract", "tool", "ignore", "use",
+	),
+}
+
+// jsonKeywords holds the literals of JSON, and those that JSON5 adds.
+var jsonKeywords = &keywordTable{
+	literals: wordSet("true", "false", "null", "Infinity", "NaN"),
+}
+
+// yamlKeywords holds the scalars that are booleans or nulls, including those
+// of YAML 1.1.
+var yamlKeywords = &keywordTable{
+	literals: wordSet(
+		"true", "True", "TRUE", "false", "False", "FALSE", "yes", "Yes", "YES",
+		"no", "No", "NO", "on", "On", "ON", "off", "Off", "OFF", "null", "Null",
+		"NULL", "~",
+	),
+}
@@ -0,0 +1,46 @@
+package syntaxhighlight
+
+import "testing"
+
+func TestKeywordTables(t *testing.T) {
+	tests := []struct {
+		lang  string
+		ident string
+		want  Kind
+	}{
+		{"go", "None", Plaintext},
+		{"go", "begin", Plaintext},
+		{"go", "alias", Plaintext},
+		{"go", "caller", Plaintext},
+		{"go", "string", Type},
+		{"go", "len", Builtin},
+		{"go", "nil", Literal},
+		{"c", "print", Plaintext},
+		{"c", "self", Plaintext},
+		{"c", "NULL", Literal},
+		{"c", "class", Plaintext},
+		{"cpp", "class", Keyword},
+		{"cpp", "nullptr", Literal},
+		{"python", "None", Literal},
+		{"python", "self", Builtin},
+		{"ruby", "begin", Keyword},
+		{"fallback", "begin", Keyword},
+		{"fallback", "None", Keyword},
+		{"protobuf", "sfixed64", Type},
+		{"graphql", "ID", Type},
+		{"graphql", "null", Literal},
+		{"clojure", "set!", Keyword},
+		{"scheme", "call/cc", Builtin},
+		{"scheme", "foo-bar?", Plaintext},
+		{"emacs-lisp", "t", Literal},
+	}
+	for _, test := range tests {
+		toks, err := Lookup(test.lang).Tokens([]byte(test.ident))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(toks) != 1 || toks[0].Kind != test.want {
+			t.Errorf("%s: %q: got %#v, want a single %#v", test.lang, test.ident, toks, test.want)
+		}
+	}
+}
@@ -4,9 +4,9 @@ package syntaxhighlight
 
 import "fmt"
 
-const _Kind_name = "WhitespaceStringKeywordCommentTypeLiteralPunctuationPlaintextTagHTMLTagHTMLAttrNameHTMLAttrValueDecimal"
+const _Kind_name = "WhitespaceStringKeywordCommentTypeLiteralPunctuationPlaintextTagHTMLTagHTMLAttrNameHTMLAttrValueDecimalBuiltinDecoratorPreprocessorInsertedDeletedFunctionMethodFieldParameterConstantPackageTypeParameter"
 
-var _Kind_index = [...]uint8{0, 10, 16, 23, 30, 34, 41, 52, 61, 64, 71, 83, 96, 103}
+var _Kind_index = [...]uint8{0, 10, 16, 23, 30, 34, 41, 52, 61, 64, 71, 83, 96, 103, 110, 119, 131, 139, 146, 154, 160, 165, 174, 182, 189, 202}
 
 func (i Kind) GoString() string {
 	if i+1 >= Kind(len(_Kind_index)) {
@@ -0,0 +1,62 @@
+package syntaxhighlight
+
+import "text/scanner"
+
+// Token is a single highlighted token of source code.
+type Token struct {
+	Kind   Kind
+	Offset int // byte offset of the token in the source
+	Text   string
+}
+
+// Lexer splits source code into tokens. The tokens returned by Tokens must
+// be in order and cover the whole source, so that concatenating their Text
+// yields src again.
+type Lexer interface {
+	Tokens(src []byte) ([]Token, error)
+}
+
+// FallbackLexer is the language-independent lexer built on text/scanner. It
+// is used when no lexer is given or none is registered for a language.
+var FallbackLexer Lexer = scannerLexer{fallbackKeywords}
+
+// scannerLexer tokenizes source code using the generic text/scanner
+// configured by NewScanner, highlighting identifiers found in its keyword
+// table.
+type scannerLexer struct {
+	keywords *keywordTable
+}
+
+func (l scannerLexer) Tokens(src []byte) ([]Token, error) {
+	s := NewScanner(src)
+
+	var toks []Token
+	read := 0
+
+	tok := s.Scan()
+	for tok != scanner.EOF {
+		tokText := s.TokenText()
+		toks = append(toks, Token{Kind: tokenKind(tok, tokText, l.keywords), Offset: read, Text: tokText})
+		read += len(tokText)
+
+		tok = s.Scan()
+	}
+
+	return toks, nil
+}
+
+func (l scannerLexer) keywordTable() *keywordTable { return l.keywords }
+
+func init() {
+	Register(LexerConfig{
+		Name: "fallback",
+	}, FallbackLexer)
+
+	// Until they get lexers of their own, the languages text/scanner
+	// performs decently on are tokenized by it, with their own keywords.
+	Register(LexerConfig{
+		Name:      "java",
+		Filenames: []string{"*.java"},
+		MimeTypes: []string{"text/x-java"},
+	}, scannerLexer{javaKeywords})
+}
@@ -0,0 +1,214 @@
+package syntaxhighlight
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"math/rand"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// checkTokens checks that toks are in order and cover all of src, and
+// reports whether they do.
+func checkTokens(t *testing.T, name string, src []byte, toks []Token) bool {
+	offset := 0
+	for i, tok := range toks {
+		if tok.Offset != offset {
+			t.Errorf("%s: token %d (%q) at offset %d, want %d", name, i, tok.Text, tok.Offset, offset)
+			return false
+		}
+		if tok.Text == "" {
+			t.Errorf("%s: token %d at offset %d is empty", name, i, offset)
+			return false
+		}
+		if offset+len(tok.Text) > len(src) || string(src[offset:offset+len(tok.Text)]) != tok.Text {
+			t.Errorf("%s: token %d (%q) does not match source at offset %d", name, i, tok.Text, offset)
+			return false
+		}
+		offset += len(tok.Text)
+	}
+	if offset != len(src) {
+		t.Errorf("%s: tokens cover %d bytes of %d", name, offset, len(src))
+		return false
+	}
+	return true
+}
+
+// TestLexersCoverSource runs every registered lexer on every test input, no
+// matter the language, and checks that the tokens cover the source.
+func TestLexersCoverSource(t *testing.T) {
+	paths, err := filepath.Glob("testdata/*")
+	if err != nil {
+		t.Fatal(err)
+	}
+	classify, err := filepath.Glob("testdata/classify/*/*")
+	if err != nil {
+		t.Fatal(err)
+	}
+	paths = append(paths, classify...)
+
+	var names []string
+	var inputs [][]byte
+	for _, path := range paths {
+		if filepath.Ext(path) == ".html" {
+			continue
+		}
+		src, err := ioutil.ReadFile(path)
+		if err != nil {
+			continue // directory
+		}
+		names = append(names, path)
+		inputs = append(inputs, src)
+	}
+
+	for _, lang := range Languages() {
+		l := Lookup(lang.Name)
+		for i, src := range inputs {
+			name := lang.Name + ": " + names[i]
+			toks, err := l.Tokens(src)
+			if err != nil {
+				t.Errorf("%s: %s", name, err)
+				continue
+			}
+			checkTokens(t, name, src, toks)
+		}
+	}
+}
+
+// TestLexersOnMalformedInput runs every registered lexer on every prefix of
+// the test inputs in its language, which cuts strings, comments and the like
+// short, on those prefixes followed by a carriage return, invalid UTF-8 or a
+// non-ASCII character, and on short random inputs made of such bytes and of
+// the delimiters of many languages. It checks that the lexer terminates,
+// doesn't panic and covers the input.
+func TestLexersOnMalformedInput(t *testing.T) {
+	paths, err := filepath.Glob("testdata/*")
+	if err != nil {
+		t.Fatal(err)
+	}
+	fragments := []string{
+		"\xff", "\xc2", "\r", "\n", "\t", " ", "é", "★", "“", "·", "\\", "/",
+		"*", "<", ">", "&", "\"", "'", "`", "$", "#", "{", "}", "[", "]", "(", ")",
+		"@", "%", "-", ":", ";", "=", "?", "!", ",", ".", "|", "a", "x", "0", "_",
+		"<?php ", "?>", "<<EOT\n", "EOT", "\"\"\"", "${", "%{", "#{", "/*", "*/",
+		"#|", "|#", "<!--", "-->", "```", "<a ", "</a>", "\\\n",
+		"<<<EOT\n", "\nEOT\n", "$(",
+	}
+	rnd := rand.New(rand.NewSource(1))
+	var random [][]byte
+	for i := 0; i < 500; i++ {
+		var b bytes.Buffer
+		for n := 1 + rnd.Intn(16); n > 0; n-- {
+			b.WriteString(fragments[rnd.Intn(len(fragments))])
+		}
+		random = append(random, b.Bytes())
+	}
+
+	// Inputs that broke lexers in the past, by language.
+	regressions := map[string][]string{
+		"ruby": {"x = <<EOS\n#{\nEOS\nbar baz\n"},
+		"php":  {"<?php\n$x = <<<EOT\n{$a\nEOT;\necho 1;\n"},
+		"hcl":  {"x = <<EOT\n${a\nEOT\ny = 1\n", "x = <<EOT\n%{if a\nEOT\ny = 1\n"},
+		"bash": {"cat <<EOF\n$(echo\nEOF\necho hi\n", "cat <<EOF\n${x\nEOF\necho hi\n", "cat <<EOF\n`echo\nEOF\necho hi\n"},
+	}
+
+	for _, lang := range Languages() {
+		l := Lookup(lang.Name)
+		inputs := random[:len(random):len(random)]
+		for _, src := range regressions[lang.Name] {
+			inputs = append(inputs, []byte(src))
+		}
+		for _, path := range paths {
+			if filepath.Ext(path) == ".html" || LookupFilename(path) != l {
+				continue
+			}
+			src, err := ioutil.ReadFile(path)
+			if err != nil {
+				continue // directory
+			}
+			// Large inputs are cut at fewer points, to keep the test fast.
+			for i, step := 0, 1+len(src)/2000; i < len(src); i += step {
+				for _, end := range []string{"", "\r", "\xff", "★"} {
+					inputs = append(inputs, append(src[:i:i], end...))
+				}
+			}
+		}
+		for _, src := range inputs {
+			name := fmt.Sprintf("%s: %q", lang.Name, src)
+			if len(src) > 40 {
+				name = fmt.Sprintf("%s: ...%q (%d bytes)", lang.Name, src[len(src)-40:], len(src))
+			}
+			if !checkLexerOn(t, name, l, src) {
+				break // report a single failure for each lexer
+			}
+		}
+	}
+}
+
+// TestLexersOnLongInput runs lexers on long inputs that take quadratic or
+// worse time to lex if, for each token, the lexer searches or copies the
+// rest of the line or file, and checks that they finish and cover the input.
+func TestLexersOnLongInput(t *testing.T) {
+	tests := []struct {
+		lang string
+		src  string
+	}{
+		{"markdown", strings.Repeat("*a ", 100000)},
+		{"markdown", strings.Repeat("**a _b ~~c ", 30000)},
+		{"markdown", strings.Repeat("`a ", 100000)},
+		{"markdown", strings.Repeat("``a\n", 100000)},
+		{"goasm", strings.Repeat("\tMOVQ x+8(FP), AX\n", 50000)},
+		{"gas", strings.Repeat("#if X\n\tmovq %rax, %rbx\n#endif\n", 30000)},
+		{"python", strings.Repeat("type = 1\n", 100000)},
+		{"javascript", strings.Repeat("`${", 20000)},
+		{"tsx", strings.Repeat("<a b={`${", 10000)},
+		{"ruby", strings.Repeat("\"#{", 20000)},
+		{"bash", strings.Repeat("\"$(", 20000)},
+		{"bash", strings.Repeat("$((\"$((", 10000)},
+		{"hcl", "x = " + strings.Repeat("\"${", 20000)},
+		{"hcl", "x = <<EOT\n" + strings.Repeat("${\"%{", 10000)},
+		{"php", "<?php " + strings.Repeat("\"{$", 20000)},
+		{"diff", "@@ -0,0 +1,200000 @@\n" + strings.Repeat("+a\n", 200000)},
+	}
+	for _, test := range tests {
+		name := fmt.Sprintf("%s: %q... (%d bytes)", test.lang, test.src[:12], len(test.src))
+		checkLexerOn(t, name, Lookup(test.lang), []byte(test.src))
+	}
+}
+
+// checkLexerOn runs l on src with a timeout and checks its tokens. It
+// reports whether they were right.
+func checkLexerOn(t *testing.T, name string, l Lexer, src []byte) bool {
+	type result struct {
+		toks  []Token
+		err   error
+		panic interface{}
+	}
+	done := make(chan result, 1)
+	go func() {
+		defer func() {
+			if p := recover(); p != nil {
+				done <- result{panic: p}
+			}
+		}()
+		toks, err := l.Tokens(src)
+		done <- result{toks: toks, err: err}
+	}()
+	select {
+	case r := <-done:
+		switch {
+		case r.panic != nil:
+			t.Errorf("%s: panic: %v", name, r.panic)
+		case r.err != nil:
+			t.Errorf("%s: %s", name, r.err)
+		default:
+			return checkTokens(t, name, src, r.toks)
+		}
+	case <-time.After(5 * time.Second):
+		t.Errorf("%s: timed out", name)
+	}
+	return false
+}
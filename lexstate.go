@@ -0,0 +1,344 @@
+package syntaxhighlight
+
+import (
+	"bytes"
+	"unicode"
+	"unicode/utf8"
+)
+
+// lexState is a cursor over source code, shared by the hand-written lexers.
+// A lexer advances pos past the text of a token and then calls emit, which
+// turns src[start:pos] into a token.
+type lexState struct {
+	src   []byte
+	start int // start of the current token
+	pos   int // end of the current token
+	toks  []Token
+}
+
+func newLexState(src []byte) *lexState {
+	return &lexState{src: src}
+}
+
+// eof reports whether the whole source has been consumed.
+func (s *lexState) eof() bool {
+	return s.pos >= len(s.src)
+}
+
+// peek returns the byte at pos+i, or 0 past the end of the source.
+func (s *lexState) peek(i int) byte {
+	if s.pos+i < len(s.src) {
+		return s.src[s.pos+i]
+	}
+	return 0
+}
+
+// peekRune returns the rune at pos and its width.
+func (s *lexState) peekRune() (rune, int) {
+	if s.eof() {
+		return 0, 0
+	}
+	return utf8.DecodeRune(s.src[s.pos:])
+}
+
+// hasPrefix reports whether the source at pos starts with prefix.
+func (s *lexState) hasPrefix(prefix string) bool {
+	return bytes.HasPrefix(s.src[s.pos:], []byte(prefix))
+}
+
+// hasPrefixFold is like hasPrefix, but ignores ASCII case.
+func (s *lexState) hasPrefixFold(prefix string) bool {
+	return len(s.src)-s.pos >= len(prefix) && bytes.EqualFold(s.src[s.pos:s.pos+len(prefix)], []byte(prefix))
+}
+
+// text returns the text of the current token.
+func (s *lexState) text() string {
+	return string(s.src[s.start:s.pos])
+}
+
+// atLineStart reports whether only spaces and tabs precede start on its line.
+func (s *lexState) atLineStart() bool {
+	for i := s.start - 1; i >= 0; i-- {
+		switch s.src[i] {
+		case ' ', '\t':
+			continue
+		case '\n':
+			return true
+		}
+		return false
+	}
+	return true
+}
+
+// emit appends the current token, if it isn't empty, with the given kind.
+func (s *lexState) emit(kind Kind) {
+	if s.pos > s.start {
+		s.toks = append(s.toks, Token{Kind: kind, Offset: s.start, Text: string(s.src[s.start:s.pos])})
+	}
+	s.start = s.pos
+}
+
+// last returns the last emitted token that isn't whitespace or a comment,
+// and whether there is one.
+func (s *lexState) last() (Token, bool) {
+	if toks := s.lastN(1); len(toks) == 1 {
+		return toks[0], true
+	}
+	return Token{Kind: Whitespace}, false
+}
+
+// lastN returns up to n of the last emitted tokens that aren't whitespace or
+// comments, in source order.
+func (s *lexState) lastN(n int) []Token {
+	var toks []Token
+	for i := len(s.toks) - 1; i >= 0 && len(toks) < n; i-- {
+		if k := s.toks[i].Kind; k != Whitespace && k != Comment {
+			toks = append([]Token{s.toks[i]}, toks...)
+		}
+	}
+	return toks
+}
+
+// delegate tokenizes the current token with another lexer, shifting the
+// offsets of its tokens to their place in the source. If l is nil, the whole
+// text becomes a single token of kind fallback.
+func (s *lexState) delegate(l Lexer, fallback Kind) {
+	if s.pos == s.start {
+		return
+	}
+	if l != nil {
+		if toks, err := l.Tokens(s.src[s.start:s.pos]); err == nil {
+			for _, tok := range toks {
+				tok.Offset += s.start
+				s.toks = append(s.toks, tok)
+			}
+			s.start = s.pos
+			return
+		}
+	}
+	s.emit(fallback)
+}
+
+// acceptByte advances past c if it is at pos, and reports whether it was.
+func (s *lexState) acceptByte(c byte) bool {
+	if !s.eof() && s.src[s.pos] == c {
+		s.pos++
+		return true
+	}
+	return false
+}
+
+// acceptWhile advances past the runes for which f returns true, and reports
+// whether it advanced at all.
+func (s *lexState) acceptWhile(f func(rune) bool) bool {
+	start := s.pos
+	for !s.eof() {
+		r, w := s.peekRune()
+		if !f(r) {
+			break
+		}
+		s.pos += w
+	}
+	return s.pos > start
+}
+
+// acceptUntil advances up to (not past) the first occurrence of delim, or to
+// the end of the source, and reports whether delim was found.
+func (s *lexState) acceptUntil(delim string) bool {
+	if i := bytes.Index(s.src[s.pos:], []byte(delim)); i >= 0 {
+		s.pos += i
+		return true
+	}
+	s.pos = len(s.src)
+	return false
+}
+
+// acceptLine advances to the end of the line, not including the newline.
+func (s *lexState) acceptLine() {
+	s.acceptUntil("\n")
+}
+
+// acceptAny advances past the longest of the given strings that the source
+// at pos starts with, and reports whether there was one.
+func (s *lexState) acceptAny(strs []string) bool {
+	best := 0
+	for _, str := range strs {
+		if len(str) > best && s.hasPrefix(str) {
+			best = len(str)
+		}
+	}
+	s.pos += best
+	return best > 0
+}
+
+// lexWhitespace emits a run of whitespace as a single token, and reports
+// whether there was any.
+func (s *lexState) lexWhitespace() bool {
+	if s.acceptWhile(unicode.IsSpace) {
+		s.emit(Whitespace)
+		return true
+	}
+	return false
+}
+
+// acceptQuoted advances past a string whose opening quote has already been
+// consumed, up to and including the closing quote. Backslash escapes the
+// next byte if escapes is set. Unless multiline is set, an unterminated
+// string ends before the newline.
+func (s *lexState) acceptQuoted(quote string, escapes, multiline bool) {
+	for !s.eof() {
+		switch {
+		case s.hasPrefix(quote):
+			s.pos += len(quote)
+			return
+		case escapes && s.peek(0) == '\\' && s.pos+1 < len(s.src):
+			s.pos += 2
+		case !multiline && s.peek(0) == '\n':
+			return
+		default:
+			s.pos++
+		}
+	}
+}
+
+// acceptNested advances past text delimited by open and close, which may
+// nest, as in /* a /* b */ c */. The open delimiter is at pos. It reports
+// whether the text was terminated.
+func (s *lexState) acceptNested(open, close string) bool {
+	s.pos += len(open)
+	depth := 1
+	for !s.eof() {
+		switch {
+		case s.hasPrefix(open):
+			depth++
+			s.pos += len(open)
+		case s.hasPrefix(close):
+			depth--
+			s.pos += len(close)
+			if depth == 0 {
+				return true
+			}
+		default:
+			s.pos++
+		}
+	}
+	return false
+}
+
+// acceptNumber advances past a number literal in the common syntax of C-like
+// languages: decimal, hex, octal and binary integers with optional digit
+// separators, floats with exponents, and any trailing letters (suffixes like
+// "u", "L" or "j"). It reports whether there was a number at pos.
+func (s *lexState) acceptNumber(separator byte) bool {
+	c := s.peek(0)
+	if !isDigit(c) && !(c == '.' && isDigit(s.peek(1))) {
+		return false
+	}
+	isDigitOrSep := func(r rune) bool { return r < utf8.RuneSelf && isDigit(byte(r)) || (separator != 0 && r == rune(separator)) }
+	if c == '0' && (s.peek(1) == 'x' || s.peek(1) == 'X') {
+		s.pos += 2
+		s.acceptWhile(func(r rune) bool { return r < utf8.RuneSelf && isHexDigit(byte(r)) || (separator != 0 && r == rune(separator)) || r == '.' })
+		if s.peek(0) == 'p' || s.peek(0) == 'P' {
+			s.acceptExponent(isDigitOrSep)
+		}
+	} else {
+		s.acceptWhile(isDigitOrSep)
+		if s.peek(0) == '.' && isDigit(s.peek(1)) || s.peek(0) == '.' && !isIdentStartByte(s.peek(1)) && s.peek(1) != '.' {
+			s.pos++
+			s.acceptWhile(isDigitOrSep)
+		}
+		if s.peek(0) == 'e' || s.peek(0) == 'E' {
+			s.acceptExponent(isDigitOrSep)
+		}
+	}
+	s.acceptWhile(func(r rune) bool { return r < utf8.RuneSelf && (isIdentStartByte(byte(r)) || isDigit(byte(r))) })
+	return true
+}
+
+// acceptExponent advances past an exponent ("e+10") if there is one.
+func (s *lexState) acceptExponent(isDigitOrSep func(rune) bool) {
+	i := 1
+	if s.peek(i) == '+' || s.peek(i) == '-' {
+		i++
+	}
+	if isDigit(s.peek(i)) {
+		s.pos += i
+		s.acceptWhile(isDigitOrSep)
+	}
+}
+
+// acceptBalanced advances past text up to (not including) the close byte that
+// balances an open byte already consumed, skipping over nested pairs and
+// over strings quoted with any of the quotes bytes. It reports whether the
+// balancing close byte was found.
+func (s *lexState) acceptBalanced(open, close byte, quotes string) bool {
+	depth := 0
+	for !s.eof() {
+		c := s.peek(0)
+		switch {
+		case c == close && depth == 0:
+			return true
+		case c == close:
+			depth--
+		case c == open:
+			depth++
+		case c != 0 && bytes.IndexByte([]byte(quotes), c) >= 0:
+			s.pos++
+			s.acceptQuoted(string(c), true, true)
+			continue
+		}
+		s.pos++
+	}
+	return false
+}
+
+// delegateBalanced advances past text up to (not including) the close byte
+// that balances an open byte before pos, as acceptBalanced does, and
+// tokenizes the text with l, as delegate does. If there is no such close
+// byte, the rest of the source is emitted as fallback instead: lexing it
+// with l, which may in turn find an open byte that isn't closed, would lex
+// it again for every such byte, in quadratic time. It reports whether the
+// close byte was found.
+func (s *lexState) delegateBalanced(open, close byte, quotes string, l Lexer, fallback Kind) bool {
+	if !s.acceptBalanced(open, close, quotes) {
+		s.emit(fallback)
+		return false
+	}
+	s.delegate(l, fallback)
+	return true
+}
+
+func isDigit(c byte) bool {
+	return '0' <= c && c <= '9'
+}
+
+func isHexDigit(c byte) bool {
+	return isDigit(c) || 'a' <= c && c <= 'f' || 'A' <= c && c <= 'F'
+}
+
+func isIdentStartByte(c byte) bool {
+	return 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z' || c == '_'
+}
+
+// isIdentStart and isIdentPart are the identifier rules of most languages:
+// a letter or underscore followed by letters, digits and underscores.
+func isIdentStart(r rune) bool {
+	return r == '_' || unicode.IsLetter(r)
+}
+
+func isIdentPart(r rune) bool {
+	return r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+// identKindByCase guesses the kind of an identifier from its case, as in
+// languages where types are capitalized and constants in all caps.
+func identKindByCase(ident string) Kind {
+	r, _ := utf8.DecodeRuneInString(ident)
+	if !unicode.IsUpper(r) {
+		return Plaintext
+	}
+	if len(ident) > 1 && ident == string(bytes.ToUpper([]byte(ident))) {
+		return Constant
+	}
+	return Type
+}
@@ -0,0 +1,396 @@
+package syntaxhighlight
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// lispDialect is a dialect of Lisp.
+type lispDialect int
+
+const (
+	lispClojure lispDialect = iota // Clojure, ClojureScript and EDN
+	lispScheme                     // Scheme, including Guile and Racket
+	lispEmacs                      // Emacs Lisp
+)
+
+// lispLexer tokenizes the source code of Lisp dialects, whose symbols, such
+// as foo-bar?, set! or ->>, may contain most punctuation. Special forms and
+// macros are highlighted as Keyword, the functions called at the head of a
+// list as Function, :keywords and quoted symbols as Literal and character
+// literals, such as \a in Clojure, as String. The quote and unquote markers,
+// such as ' and ,@, abbreviate special forms and are highlighted as Keyword.
+type lispLexer struct {
+	dialect lispDialect
+}
+
+func init() {
+	Register(LexerConfig{
+		Name:         "clojure",
+		Aliases:      []string{"clj", "cljs"},
+		Filenames:    []string{"*.clj", "*.cljs", "*.cljc", "*.edn"},
+		MimeTypes:    []string{"text/x-clojure", "application/x-clojure"},
+		Interpreters: []string{"clojure", "bb"},
+	}, lispLexer{lispClojure})
+	Register(LexerConfig{
+		Name:         "scheme",
+		Aliases:      []string{"scm"},
+		Filenames:    []string{"*.scm", "*.ss", "*.sld", "*.sls"},
+		MimeTypes:    []string{"text/x-scheme"},
+		Interpreters: []string{"guile", "scheme", "csi"},
+	}, lispLexer{lispScheme})
+	Register(LexerConfig{
+		Name:      "emacs-lisp",
+		Aliases:   []string{"elisp", "emacs"},
+		Filenames: []string{"*.el", ".emacs", "_emacs"},
+		MimeTypes: []string{"text/x-elisp"},
+	}, lispLexer{lispEmacs})
+}
+
+// lispSyntax is the syntax that a Lisp dialect doesn't share with the others.
+type lispSyntax struct {
+	keywords *keywordTable
+	openers  []string // the brackets that open lists and other collections, such as #{ for a set
+	markers  []string // the quote and unquote markers, and other reader macros that prefix a form
+}
+
+var (
+	lispSyntaxes = [...]lispSyntax{
+		lispClojure: {
+			keywords: clojureKeywords,
+			openers:  []string{"(", "[", "{", "#(", "#{", "#?(", "#?@("},
+			markers:  []string{"'", "`", "~", "~@", "@", "#'", "^"},
+		},
+		lispScheme: {
+			keywords: schemeKeywords,
+			openers:  []string{"(", "[", "{", "#(", "#u8(", "#vu8("},
+			markers:  []string{"'", "`", ",", ",@", "#'", "#`", "#,", "#,@"},
+		},
+		lispEmacs: {
+			keywords: emacsLispKeywords,
+			openers:  []string{"(", "[", "#s("},
+			markers:  []string{"'", "`", ",", ",@", "#'"},
+		},
+	}
+
+	// lispQuotes are the markers that quote the form they prefix, which is
+	// then data rather than code, unless it is unquoted.
+	lispQuotes = wordSet("'", "`", "#`")
+
+	// lispUnquotes are the markers that unquote the form they prefix.
+	lispUnquotes = wordSet(",", ",@", "~", "~@", "#,", "#,@")
+
+	// lispNumber matches the numbers of all dialects, such as -42, 1.5e3,
+	// 22/7, 0xFF, 2r1010 or, in Clojure, 42N and 1.5M.
+	lispNumber = regexp.MustCompile(`^[+-]?(0[xX][0-9a-fA-F]+|[0-9]+[rR][0-9a-zA-Z]+|([0-9]+\.?[0-9]*|\.[0-9]+)([eE][+-]?[0-9]+)?(/[0-9]+)?)[NM]?$`)
+
+	// lispRadixNumber matches the numbers of Scheme and Emacs Lisp that
+	// start with a radix or exactness prefix, such as #xFF, #b1010 or
+	// #e1.5.
+	lispRadixNumber = regexp.MustCompile(`^#([xXbBoOdDeEiI]|[0-9]+r)[0-9a-zA-Z.+\-/#]+$`)
+)
+
+// lispRole is the role of a list in the code around it.
+type lispRole int
+
+const (
+	lispCode      lispRole = iota
+	lispData               // quoted data, such as '(a b c)
+	lispParams             // the parameters of a function, such as (x &optional y)
+	lispSignature          // the signature of a function in a define, such as (square x)
+	lispBindings           // the bindings of a let, such as ((x 1) (y 2))
+	lispBinding            // a binding, such as (x 1), whose head is a variable
+)
+
+// lispList is a list, vector or other collection that encloses pos.
+type lispList struct {
+	role  lispRole
+	call  bool   // whether the head of the list is called, as in (f x), unlike in [f x]
+	head  string // the symbol at the head of the list, if any
+	forms int    // the number of forms in the list so far
+	colls int    // the number of collections in the list so far
+}
+
+// lispLexState is the state of the Lisp lexer.
+type lispLexState struct {
+	*lexState
+	lists  []*lispList // the enclosing lists, innermost last
+	marker string      // the quote or unquote marker before the form at pos, if any
+}
+
+func (l lispLexer) Tokens(src []byte) ([]Token, error) {
+	s := &lispLexState{lexState: newLexState(src)}
+	for !s.eof() {
+		l.lex(s)
+	}
+	return s.toks, nil
+}
+
+func (l lispLexer) keywordTable() *keywordTable { return lispSyntaxes[l.dialect].keywords }
+
+// list returns the innermost list that encloses pos, or nil at top level.
+func (s *lispLexState) list() *lispList {
+	if len(s.lists) == 0 {
+		return nil
+	}
+	return s.lists[len(s.lists)-1]
+}
+
+// role returns the role of the collection that the opener at start opens.
+func (l lispLexer) role(s *lispLexState) lispRole {
+	if _, ok := lispQuotes[s.marker]; ok {
+		return lispData
+	}
+	if l.dialect != lispClojure && s.text()[0] == '#' {
+		return lispData // a literal vector or record, such as #(1 2) or #s(point 1 2)
+	}
+	parent := s.list()
+	if _, ok := lispUnquotes[s.marker]; ok || parent == nil {
+		return lispCode
+	}
+	switch parent.role {
+	case lispData, lispParams:
+		return parent.role // a quoted list, or the destructuring of a parameter
+	case lispBindings:
+		return lispBinding
+	}
+	// Only the first collection in a form may be a parameter list or the
+	// like, as in (defn f "Doc." [x] ...) or (let loop ((i 0)) ...).
+	if parent.colls > 0 {
+		return lispCode
+	}
+	vector := s.text() == "["
+	switch parent.head {
+	case "fn", "defn", "defn-", "defmacro", "lambda", "defun", "defsubst", "cl-defun", "cl-defmacro":
+		if vector == (l.dialect == lispClojure) {
+			return lispParams
+		}
+	case "define":
+		return lispSignature
+	case "let", "let*", "letrec", "letrec*", "let-values", "let*-values", "pcase-let", "pcase-let*":
+		if l.dialect != lispClojure {
+			return lispBindings
+		}
+	case "dolist", "dotimes", "when-let", "if-let":
+		if l.dialect == lispEmacs {
+			return lispBinding
+		}
+	}
+	return lispCode
+}
+
+// lex lexes a single Lisp token.
+func (l lispLexer) lex(s *lispLexState) {
+	if s.lexWhitespace() {
+		return
+	}
+	syntax := &lispSyntaxes[l.dialect]
+	c := s.peek(0)
+	switch {
+	case c == ';':
+		s.acceptLine()
+		s.emit(Comment)
+		return
+	case s.hasPrefix("#|") && l.dialect != lispClojure:
+		s.acceptNested("#|", "|#")
+		s.emit(Comment)
+		return
+	case s.hasPrefix("#!") && (s.pos == 0 || l.dialect == lispScheme && s.atLineStart()):
+		// A shebang line, or a directive of Scheme, such as #!r6rs.
+		s.acceptLine()
+		s.emit(Comment)
+		return
+	case s.hasPrefix("#_") && l.dialect == lispClojure, s.hasPrefix("#;") && l.dialect == lispScheme:
+		// A datum comment, which comments out the form that follows it.
+		s.pos += 2
+		lispAcceptForm(s.lexState)
+		s.emit(Comment)
+		return
+	case c == '"', s.hasPrefix(`#"`) && l.dialect == lispClojure:
+		// A string, or a regular expression of Clojure, such as #"\d+".
+		if c == '#' {
+			s.pos++
+		}
+		s.pos++
+		s.acceptQuoted(`"`, true, true)
+		s.emit(String)
+	case l.acceptChar(s):
+		s.emit(String)
+	case s.acceptAny(syntax.openers):
+		list := &lispList{role: l.role(s)}
+		// The heads of lists, and in Clojure of anonymous functions such as
+		// #(* % 2), are called, unlike those of vectors or maps.
+		list.call = s.text() == "(" || l.dialect == lispClojure && s.text() == "#("
+		if parent := s.list(); parent != nil {
+			parent.forms++
+			parent.colls++
+		}
+		s.lists = append(s.lists, list)
+		s.marker = ""
+		s.emit(Punctuation)
+		return
+	case c == ')' || c == ']' || c == '}':
+		s.pos++
+		if len(s.lists) > 0 {
+			s.lists = s.lists[:len(s.lists)-1]
+		}
+		s.marker = ""
+		s.emit(Punctuation)
+		return
+	case s.acceptAny(syntax.markers):
+		s.marker = s.text()
+		if s.marker == "^" {
+			s.emit(Decorator) // metadata, such as ^:private or the type hint ^String
+		} else {
+			s.emit(Keyword)
+		}
+		return
+	default:
+		if c == '#' {
+			s.pos++
+		}
+		if !s.acceptWhile(l.isSymbolPart) && c != '#' {
+			_, w := s.peekRune()
+			s.pos += w
+			s.emit(Punctuation)
+			break
+		}
+		s.emit(l.symbolKind(s))
+	}
+	if list := s.list(); list != nil {
+		list.forms++
+	}
+	s.marker = ""
+}
+
+// isSymbolPart reports whether r may be part of a symbol.
+func (l lispLexer) isSymbolPart(r rune) bool {
+	switch r {
+	case '(', ')', '[', ']', '{', '}', '"', ';', ',', '`':
+		return false
+	case '\'':
+		return l.dialect == lispClojure // as in x'
+	case '\\':
+		return l.dialect != lispClojure // which escapes the next character in Emacs Lisp
+	}
+	return !unicode.IsSpace(r)
+}
+
+// symbolKind returns the kind of the symbol, keyword, number or other atom
+// that is the current token.
+func (l lispLexer) symbolKind(s *lispLexState) Kind {
+	sym := s.text()
+	if sym[0] == '#' {
+		switch {
+		case l.dialect == lispClojure && strings.HasPrefix(sym, "##"):
+			return Decimal // ##Inf, ##-Inf or ##NaN
+		case l.dialect == lispClojure && len(sym) > 1:
+			return Decorator // the tag of a tagged literal, such as #inst
+		case lispRadixNumber.MatchString(sym):
+			return Decimal
+		case sym == "#t", sym == "#f", sym == "#true", sym == "#false", strings.HasPrefix(sym, "#:"):
+			return Literal
+		}
+		return Plaintext
+	}
+	switch {
+	case lispNumber.MatchString(sym):
+		return Decimal
+	case sym[0] == ':' && len(sym) > 1:
+		return Literal // a keyword, such as :key or ::key
+	case s.marker == "#'":
+		return Function
+	}
+	if _, ok := lispQuotes[s.marker]; ok {
+		return Literal
+	}
+
+	prev, _ := s.last()
+	if prev.Text == "^" {
+		return Type
+	}
+	list := s.list()
+	if list != nil && list.forms == 0 {
+		list.head = sym
+	}
+	kind, ok := l.keywordTable().kind(sym)
+	switch {
+	case list != nil && list.role == lispParams && kind != Keyword,
+		list != nil && list.role == lispSignature && list.forms > 0:
+		return Parameter
+	case ok:
+		return kind
+	case prev.Kind == Keyword:
+		switch prev.Text {
+		case "defn", "defn-", "defmacro", "defmulti", "defmethod", "defun", "defsubst", "cl-defun", "define-syntax", "define-minor-mode", "define-derived-mode":
+			return Function
+		case "defrecord", "deftype", "defprotocol", "definterface", "define-record-type":
+			return Type
+		case "ns":
+			return Package
+		}
+	}
+	if list != nil && list.forms == 0 && list.call {
+		switch list.role {
+		case lispCode, lispSignature:
+			return Function
+		case lispBinding:
+			return Parameter // as in (let ((x 1)) ...)
+		}
+	}
+	return Plaintext
+}
+
+// acceptChar advances past a character literal, such as \a or \newline in
+// Clojure, #\a or #\space in Scheme and ?a or ?\C-x in Emacs Lisp, and
+// reports whether there was one at pos.
+func (l lispLexer) acceptChar(s *lispLexState) bool {
+	switch {
+	case l.dialect == lispClojure && s.peek(0) == '\\':
+		s.pos++
+	case l.dialect == lispScheme && s.hasPrefix(`#\`):
+		s.pos += 2
+	case l.dialect == lispEmacs && s.peek(0) == '?' && s.pos+1 < len(s.src):
+		s.pos++
+		for s.acceptByte('\\') {
+			if strings.IndexByte("ACHMSs", s.peek(0)) >= 0 && s.peek(1) == '-' {
+				s.pos += 2 // a modifier, as in ?\C-\M-x
+				continue
+			}
+			s.acceptByte('^') // a control character, as in ?\^I
+			break
+		}
+		_, w := s.peekRune()
+		s.pos += w
+		return true
+	default:
+		return false
+	}
+	// A named or numbered character, such as \space, é or #\x41.
+	r, w := s.peekRune()
+	s.pos += w
+	if unicode.IsLetter(r) {
+		s.acceptWhile(func(r rune) bool { return unicode.IsLetter(r) || unicode.IsDigit(r) })
+	}
+	return true
+}
+
+// lispAcceptForm advances past the form after pos, with the whitespace and
+// markers before it.
+func lispAcceptForm(s *lexState) {
+	s.acceptWhile(unicode.IsSpace)
+	s.acceptWhile(func(r rune) bool { return strings.ContainsRune("'`,@~^#", r) })
+	switch c := s.peek(0); c {
+	case '(', '[', '{':
+		s.pos++
+		if s.acceptBalanced(c, ")]}"[strings.IndexByte("([{", c)], `"`) {
+			s.pos++
+		}
+	case '"':
+		s.pos++
+		s.acceptQuoted(`"`, true, true)
+	default:
+		s.acceptWhile(func(r rune) bool { return !unicode.IsSpace(r) && !strings.ContainsRune(`()[]{}";`, r) })
+	}
+}
@@ -0,0 +1,391 @@
+package syntaxhighlight
+
+import (
+	"bytes"
+	"strings"
+)
+
+// makefileLexer tokenizes Makefiles. Targets are highlighted as Function,
+// variables and references to them, such as $(VAR) and $@, as Parameter,
+// the functions of GNU make and special targets as Builtin and directives as
+// Keyword. Recipe lines are tokenized by the shell lexer.
+type makefileLexer struct{}
+
+func init() {
+	Register(LexerConfig{
+		Name:         "makefile",
+		Aliases:      []string{"make", "mf"},
+		Filenames:    []string{"Makefile", "makefile", "GNUmakefile", "*.mk", "*.mak"},
+		MimeTypes:    []string{"text/x-makefile"},
+		Interpreters: []string{"make"},
+	}, makefileLexer{})
+}
+
+var makefileAssignments = []string{"=", ":=", "::=", ":::=", "?=", "+=", "!="}
+
+// makefileLexState is the state of the Makefile lexer.
+type makefileLexState struct {
+	*lexState
+	inRule bool // whether lines that start with a tab are recipe lines
+}
+
+func (makefileLexer) Tokens(src []byte) ([]Token, error) {
+	s := &makefileLexState{lexState: newLexState(src)}
+	for !s.eof() {
+		lexMakefileLine(s)
+	}
+	return s.toks, nil
+}
+
+func (makefileLexer) keywordTable() *keywordTable { return makefileKeywords }
+
+// makefileIsSpecialTarget reports whether target is one of the special
+// targets of GNU make, such as .PHONY.
+func makefileIsSpecialTarget(target string) bool {
+	kind, _ := makefileKeywords.kind(target)
+	return kind == Builtin && strings.HasPrefix(target, ".")
+}
+
+func isMakefileBlank(r rune) bool {
+	return r == ' ' || r == '\t' || r == '\r'
+}
+
+// lexMakefileLine lexes a newline, or a logical line, which continues onto
+// the next line if it ends with a backslash.
+func lexMakefileLine(s *makefileLexState) {
+	if s.acceptByte('\n') {
+		s.emit(Whitespace)
+		return
+	}
+	end := makefileLineEnd(s.src, s.pos)
+	if s.peek(0) == '\t' && s.inRule && s.atLineStart() {
+		s.pos++
+		s.emit(Whitespace)
+		lexMakefileRecipe(s.lexState, end)
+		return
+	}
+	if s.acceptWhile(isMakefileBlank) {
+		s.emit(Whitespace)
+		return
+	}
+	if s.peek(0) == '#' {
+		s.pos = end
+		s.emit(Comment)
+		return
+	}
+
+	word := s.src[s.pos:end]
+	if i := bytes.IndexAny(word, " \t"); i >= 0 {
+		word = word[:i]
+	}
+	if kind, _ := makefileKeywords.kind(string(word)); kind == Keyword && !makefileIsAssignment(s.src[s.pos+len(word):end]) {
+		s.pos += len(word)
+		s.emit(Keyword)
+		switch string(word) {
+		case "define":
+			lexMakefileDefine(s, end)
+		case "export", "override", "private":
+			// An assignment may follow.
+		default:
+			lexMakefileText(s.lexState, end, Plaintext)
+		}
+		return
+	}
+
+	// The line is an assignment or a rule, depending on whether an
+	// assignment operator or a colon comes first.
+	for s.pos < end {
+		switch c := s.peek(0); {
+		case c == '$' && lexMakefileReference(s.lexState):
+		case makefileIsAssignment(s.src[s.pos:end]):
+			lexMakefileAssignment(s.lexState, end)
+			s.inRule = false
+			return
+		case c == ':':
+			lexMakefileRule(s, end)
+			s.inRule = true
+			return
+		case isMakefileBlank(rune(c)):
+			s.acceptWhile(isMakefileBlank)
+			s.emit(Whitespace)
+		default:
+			// The name of a variable or a target, which is told apart by
+			// the operator that follows.
+			s.pos++
+			for s.pos < end && strings.IndexByte(" \t\r$:", s.peek(0)) < 0 && !makefileIsAssignment(s.src[s.pos:end]) {
+				s.pos++
+			}
+			switch {
+			case makefileIsAssignment(s.src[s.pos:end]):
+				s.emit(Parameter)
+			case makefileIsSpecialTarget(s.text()):
+				s.emit(Builtin)
+			default:
+				s.emit(Function)
+			}
+		}
+	}
+}
+
+// makefileLineEnd returns the end of the logical line at i, which continues
+// past newlines escaped with a backslash.
+func makefileLineEnd(src []byte, i int) int {
+	for {
+		nl := bytes.IndexByte(src[i:], '\n')
+		if nl < 0 {
+			return len(src)
+		}
+		if nl == 0 || src[i+nl-1] != '\\' {
+			return i + nl
+		}
+		i += nl + 1
+	}
+}
+
+// makefileIsAssignment reports whether rest, the rest of a line after the
+// name of a variable, starts with an assignment operator.
+func makefileIsAssignment(rest []byte) bool {
+	rest = bytes.TrimLeft(rest, " \t")
+	for _, op := range makefileAssignments {
+		if bytes.HasPrefix(rest, []byte(op)) {
+			return true
+		}
+	}
+	return false
+}
+
+// isMakefileSpace reports whether the byte at i is whitespace or the
+// backslash of an escaped newline.
+func isMakefileSpace(src []byte, i int) bool {
+	switch src[i] {
+	case ' ', '\t', '\r', '\n':
+		return true
+	case '\\':
+		return i+1 < len(src) && src[i+1] == '\n'
+	}
+	return false
+}
+
+// lexMakefileAssignment lexes the assignment operator at pos and the value
+// that follows it, up to end. The value of != is a shell command.
+func lexMakefileAssignment(s *lexState, end int) {
+	s.acceptWhile(isMakefileBlank)
+	s.emit(Whitespace)
+	s.acceptAny(makefileAssignments)
+	op := s.text()
+	s.emit(Punctuation)
+	s.acceptWhile(isMakefileBlank)
+	s.emit(Whitespace)
+	if op == "!=" {
+		lexMakefileRecipe(s, end)
+	} else {
+		lexMakefileText(s, end, Plaintext)
+	}
+}
+
+// lexMakefileRule lexes the rest of a rule after its targets: the colon, the
+// prerequisites and an optional recipe after a semicolon. An assignment to a
+// target-specific variable may take the place of the prerequisites.
+func lexMakefileRule(s *makefileLexState, end int) {
+	s.acceptByte(':')
+	s.acceptByte(':')
+	s.emit(Punctuation)
+	for s.pos < end {
+		switch c := s.peek(0); {
+		case c == ';':
+			s.pos++
+			s.emit(Punctuation)
+			lexMakefileRecipe(s.lexState, end)
+		case c == '#':
+			s.pos = end
+			s.emit(Comment)
+		case c == '|':
+			s.pos++
+			s.emit(Punctuation) // order-only prerequisites follow
+		case c == '$' && lexMakefileReference(s.lexState):
+		case isMakefileSpace(s.src, s.pos):
+			for s.pos < end && isMakefileSpace(s.src, s.pos) {
+				s.pos++
+			}
+			s.emit(Whitespace)
+		default:
+			s.pos++
+			for s.pos < end && strings.IndexByte("$;#|", s.peek(0)) < 0 && !isMakefileSpace(s.src, s.pos) && !makefileIsAssignment(s.src[s.pos:end]) {
+				s.pos++
+			}
+			if makefileIsAssignment(s.src[s.pos:end]) {
+				s.emit(Parameter)
+				lexMakefileAssignment(s.lexState, end)
+				return
+			}
+			s.emit(Plaintext)
+		}
+	}
+}
+
+// lexMakefileDefine lexes the rest of the first line of a multi-line
+// variable defined with define, its value, which is highlighted as String,
+// and the endef that ends it.
+func lexMakefileDefine(s *makefileLexState, end int) {
+	s.acceptWhile(isMakefileBlank)
+	s.emit(Whitespace)
+	for s.pos < end && !isMakefileBlank(rune(s.peek(0))) && !makefileIsAssignment(s.src[s.pos:end]) {
+		s.pos++
+	}
+	s.emit(Parameter)
+	lexMakefileText(s.lexState, end, Punctuation)
+
+	for i := end + 1; i < len(s.src); {
+		lineEnd := bytes.IndexByte(s.src[i:], '\n')
+		if lineEnd < 0 {
+			lineEnd = len(s.src)
+		} else {
+			lineEnd += i
+		}
+		if bytes.Equal(bytes.TrimSpace(s.src[i:lineEnd]), []byte("endef")) {
+			lexMakefileText(s.lexState, i, String)
+			s.acceptWhile(isMakefileBlank)
+			s.emit(Whitespace)
+			s.pos += len("endef")
+			s.emit(Keyword)
+			return
+		}
+		i = lineEnd + 1
+	}
+	lexMakefileText(s.lexState, len(s.src), String)
+}
+
+// lexMakefileText lexes text up to end that may contain references to
+// variables and calls of functions, highlighting the rest of it as kind.
+// Unless kind is String, a "#" starts a comment.
+func lexMakefileText(s *lexState, end int, kind Kind) {
+	for s.pos < end {
+		switch c := s.peek(0); {
+		case c == '$' && lexMakefileReference(s):
+		case c == '#' && kind != String:
+			s.pos = end
+			s.emit(Comment)
+		case isMakefileSpace(s.src, s.pos):
+			for s.pos < end && isMakefileSpace(s.src, s.pos) {
+				s.pos++
+			}
+			s.emit(Whitespace)
+		default:
+			s.pos++
+			for s.pos < end && strings.IndexByte("$#", s.peek(0)) < 0 && !isMakefileSpace(s.src, s.pos) {
+				s.pos++
+			}
+			s.emit(kind)
+		}
+	}
+}
+
+// lexMakefileReference lexes a reference to a variable, such as $(VAR),
+// ${VAR} or $@, or a call of a function, such as $(patsubst %.c,%.o,$(SRCS)),
+// and reports whether the "$" at pos starts one. $$, which stands for a
+// literal "$", is highlighted as Punctuation.
+func lexMakefileReference(s *lexState) bool {
+	switch open := s.peek(1); {
+	case open == '$':
+		s.pos += 2
+		s.emit(Punctuation)
+	case open == '(' || open == '{':
+		close := byte(')')
+		if open == '{' {
+			close = '}'
+		}
+		// Find the end of the reference first, as a "$(" without one is
+		// just a "$".
+		ref := &lexState{src: s.src, pos: s.pos + 2}
+		if !ref.acceptBalanced(open, close, "") {
+			s.pos++
+			s.emit(Plaintext)
+			return true
+		}
+		s.pos += 2
+		for s.pos < ref.pos && (isIdentPart(rune(s.peek(0))) || s.peek(0) == '-') {
+			s.pos++
+		}
+		if kind, _ := makefileKeywords.kind(s.text()[2:]); kind != Builtin || !isMakefileBlank(rune(s.peek(0))) {
+			s.pos = ref.pos + 1
+			s.emit(Parameter)
+			return true
+		}
+		name := s.pos
+		s.pos = s.start + 2
+		s.emit(Punctuation)
+		s.pos = name
+		s.emit(Builtin)
+		lexMakefileText(s, ref.pos, Plaintext)
+		s.pos++
+		s.emit(Punctuation)
+	case open != 0 && open != '\n' && !isMakefileBlank(rune(open)):
+		// An automatic variable, such as $@ or $<, or a variable whose name
+		// is a single character.
+		s.pos += 2
+		s.emit(Parameter)
+	default:
+		return false
+	}
+	return true
+}
+
+// lexMakefileRecipe lexes a recipe line up to end: its prefixes, such as @,
+// which keeps make from echoing it, and its shell command. The command is
+// tokenized by the shell lexer with the references to make variables in it
+// masked, so that they don't disturb its quoting.
+func lexMakefileRecipe(s *lexState, end int) {
+	src := s.src
+	s.src = s.src[:end]
+	defer func() { s.src = src }()
+
+	s.acceptWhile(func(r rune) bool { return r == '@' || r == '-' || r == '+' })
+	s.emit(Punctuation)
+
+	var refs [][2]int // the starts and ends of the references
+	base := s.pos
+	masked := []byte(string(s.src[base:]))
+	for i := base; i < end; i++ {
+		if s.src[i] != '$' {
+			continue
+		}
+		ref := &lexState{src: s.src, start: i, pos: i}
+		if !lexMakefileReference(ref) {
+			continue
+		}
+		refs = append(refs, [2]int{i, ref.pos})
+		for j := i; j < ref.pos; j++ {
+			masked[j-base] = 'x'
+		}
+		if string(s.src[i:ref.pos]) == "$$" {
+			masked[i+1-base] = '$' // a "$" for the shell
+		}
+		i = ref.pos - 1
+	}
+
+	toks, _ := shellLexer{}.Tokens(masked)
+	for _, tok := range toks {
+		for start, tokEnd := base+tok.Offset, base+tok.Offset+len(tok.Text); start < tokEnd; {
+			if len(refs) > 0 && start >= refs[0][0] {
+				// Lex the reference itself, in place of the tokens of its
+				// mask.
+				s.pos = refs[0][0]
+				lexMakefileReference(s)
+				start = refs[0][1]
+				refs = refs[1:]
+				continue
+			}
+			pieceEnd := tokEnd
+			if len(refs) > 0 && refs[0][0] < pieceEnd {
+				pieceEnd = refs[0][0]
+			}
+			if pieceEnd > s.pos {
+				s.pos = pieceEnd
+				s.emit(tok.Kind)
+			}
+			start = pieceEnd
+		}
+	}
+	s.pos = end
+	s.emit(Plaintext)
+}
@@ -0,0 +1,464 @@
+package syntaxhighlight
+
+import (
+	"bytes"
+	"strings"
+)
+
+// markdownLexer tokenizes Markdown documents. Headings are highlighted as
+// Keyword, list markers as Keyword, emphasis as Literal, inline code as
+// String and link destinations as HTMLAttrValue; prose is Plaintext. Fenced
+// code blocks are tokenized by the lexer that their info string names, such
+// as "go" in ```go, and YAML front matter by the YAML lexer.
+type markdownLexer struct{}
+
+func init() {
+	Register(LexerConfig{
+		Name:      "markdown",
+		Aliases:   []string{"md"},
+		Filenames: []string{"*.md", "*.markdown", "*.mdown", "*.mkd"},
+		MimeTypes: []string{"text/markdown", "text/x-markdown"},
+	}, markdownLexer{})
+}
+
+// markdownLexState is the state of the Markdown lexer.
+type markdownLexState struct {
+	*lexState
+
+	// unclosed holds, for the delimiters of code spans and emphasis (such as
+	// "`" or "**"), the position up to which a search for a closing delimiter
+	// failed. An opener before that position has no closer either, so the
+	// search isn't repeated for every opener of a long line or paragraph.
+	unclosed map[string]int
+}
+
+func (markdownLexer) Tokens(src []byte) ([]Token, error) {
+	s := &markdownLexState{lexState: newLexState(src), unclosed: make(map[string]int)}
+	if s.hasPrefix("---\n") {
+		lexMarkdownFrontMatter(s.lexState)
+	}
+	for !s.eof() {
+		lexMarkdownLine(s)
+	}
+	return s.toks, nil
+}
+
+// lexMarkdownFrontMatter lexes the YAML front matter that starts the
+// document, delimited by lines of "---".
+func lexMarkdownFrontMatter(s *lexState) {
+	end := bytes.Index(s.src[3:], []byte("\n---"))
+	if end < 0 {
+		return
+	}
+	end += 3 + 1
+	s.pos += 3
+	s.emit(Keyword)
+	s.pos = end
+	s.delegate(yamlLexer{}, String)
+	s.pos += 3
+	s.emit(Keyword)
+}
+
+// lexMarkdownLine lexes a line: its block markers, such as those of block
+// quotes and lists, and then its contents.
+func lexMarkdownLine(s *markdownLexState) {
+	for {
+		s.acceptWhile(func(r rune) bool { return r == ' ' || r == '\t' })
+		s.emit(Whitespace)
+		switch {
+		case s.peek(0) == '>':
+			s.pos++
+			s.emit(Punctuation)
+			continue
+		case markdownIsThematicBreak(s.lexState):
+			s.acceptLine()
+			s.emit(Punctuation)
+		case acceptMarkdownListMarker(s.lexState):
+			s.emit(Keyword)
+			continue
+		}
+		break
+	}
+
+	c := s.peek(0)
+	switch {
+	case s.hasPrefix("```") || s.hasPrefix("~~~"):
+		lexMarkdownFence(s.lexState)
+		return
+	case c == '#':
+		n := 0
+		for s.peek(n) == '#' {
+			n++
+		}
+		if n <= 6 && (isMarkdownSpace(s.peek(n)) || s.pos+n == len(s.src)) {
+			s.acceptLine()
+			s.emit(Keyword)
+		}
+	case c == '=' && markdownIsUnderline(s.lexState, '='):
+		// The underline of a setext heading.
+		s.acceptLine()
+		s.emit(Keyword)
+	case c == '[' && markdownIsLinkDefinition(s.lexState):
+		lexMarkdownLinkDefinition(s.lexState)
+	}
+
+	end := bytes.IndexByte(s.src[s.pos:], '\n')
+	if end < 0 {
+		end = len(s.src)
+	} else {
+		end += s.pos
+	}
+	lexMarkdownInline(s, end)
+	s.lexWhitespace()
+}
+
+// markdownIsThematicBreak reports whether the rest of the line is a thematic
+// break: three or more "*", "-" or "_", optionally separated by spaces.
+func markdownIsThematicBreak(s *lexState) bool {
+	c := s.peek(0)
+	if c != '*' && c != '-' && c != '_' {
+		return false
+	}
+	n := 0
+	for i := s.pos; i < len(s.src) && s.src[i] != '\n'; i++ {
+		switch s.src[i] {
+		case c:
+			n++
+		case ' ', '\t', '\r':
+		default:
+			return false
+		}
+	}
+	return n >= 3
+}
+
+// markdownIsUnderline reports whether the rest of the line consists of c
+// alone, as in the underline of a setext heading.
+func markdownIsUnderline(s *lexState, c byte) bool {
+	line := s.src[s.pos:]
+	if i := bytes.IndexByte(line, '\n'); i >= 0 {
+		line = line[:i]
+	}
+	return len(bytes.Trim(bytes.TrimSpace(line), string(c))) == 0
+}
+
+// acceptMarkdownListMarker advances past a bullet or ordered list marker,
+// such as "-" or "1.", that is followed by a space, and reports whether there
+// was one at pos.
+func acceptMarkdownListMarker(s *lexState) bool {
+	n := 0
+	for n < 9 && isDigit(s.peek(n)) {
+		n++
+	}
+	switch c := s.peek(n); {
+	case n == 0 && (c == '-' || c == '*' || c == '+'), n > 0 && (c == '.' || c == ')'):
+		if isMarkdownSpace(s.peek(n + 1)) {
+			s.pos += n + 1
+			return true
+		}
+	}
+	return false
+}
+
+func isMarkdownSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\r' || c == '\n'
+}
+
+// lexMarkdownFence lexes a fenced code block, whose contents are tokenized
+// by the lexer named by its info string, or else emitted as a String.
+func lexMarkdownFence(s *lexState) {
+	fence := s.peek(0)
+	for s.peek(0) == fence {
+		s.pos++
+	}
+	n := s.pos - s.start
+	s.emit(Punctuation)
+	s.acceptWhile(func(r rune) bool { return r == ' ' })
+	s.emit(Whitespace)
+	s.acceptWhile(func(r rune) bool { return r != '\n' && r != ' ' && r != '{' && r != '`' })
+	info := strings.ToLower(s.text())
+	s.emit(Keyword)
+	s.acceptLine()
+	s.emit(Plaintext) // any attributes that follow the language
+	if !s.acceptByte('\n') {
+		return
+	}
+	s.emit(Whitespace)
+
+	var l Lexer
+	if info != "" {
+		if l = Lookup(info); l == nil {
+			l = LookupFilename("file." + info)
+		}
+	}
+
+	// The block ends at a line of at least n fence characters, or at the end
+	// of the enclosing document.
+	end, closing := len(s.src), len(s.src)
+	for i := s.pos; i < len(s.src); {
+		lineEnd := bytes.IndexByte(s.src[i:], '\n')
+		if lineEnd < 0 {
+			lineEnd = len(s.src)
+		} else {
+			lineEnd += i
+		}
+		line := bytes.TrimLeft(s.src[i:lineEnd], " ")
+		if m := len(line) - len(bytes.TrimLeft(line, string(fence))); m >= n && len(bytes.TrimSpace(line[m:])) == 0 {
+			end, closing = i, lineEnd-len(line)
+			break
+		}
+		i = lineEnd + 1
+	}
+	s.pos = end
+	s.delegate(l, String)
+	s.pos = closing
+	s.emit(Whitespace)
+	s.acceptWhile(func(r rune) bool { return r == rune(fence) })
+	s.emit(Punctuation)
+}
+
+// markdownIsLinkDefinition reports whether the line at pos is a link
+// reference definition, such as [label]: https://example.com.
+func markdownIsLinkDefinition(s *lexState) bool {
+	i := bytes.IndexByte(s.src[s.pos:], ']')
+	nl := bytes.IndexByte(s.src[s.pos:], '\n')
+	return i > 1 && (nl < 0 || i < nl) && s.peek(i+1) == ':'
+}
+
+// lexMarkdownLinkDefinition lexes a link reference definition: its label,
+// which is highlighted as Constant, and its destination.
+func lexMarkdownLinkDefinition(s *lexState) {
+	s.pos++
+	s.emit(Punctuation)
+	s.acceptUntil("]")
+	s.emit(Constant)
+	s.pos += 2
+	s.emit(Punctuation)
+	s.acceptWhile(func(r rune) bool { return r == ' ' || r == '\t' })
+	s.emit(Whitespace)
+	s.acceptWhile(func(r rune) bool { return !isMarkdownSpace(byte(r)) || r >= 0x80 })
+	s.emit(HTMLAttrValue)
+	s.acceptWhile(func(r rune) bool { return r == ' ' || r == '\t' })
+	s.emit(Whitespace)
+	s.acceptLine()
+	s.emit(String) // a title
+}
+
+// lexMarkdownInline lexes the inline contents of a block up to end: code
+// spans, emphasis, links, autolinks, raw HTML, entities and plain text.
+func lexMarkdownInline(s *markdownLexState, end int) {
+	for s.pos < end {
+		c := s.peek(0)
+		switch {
+		case c == '\\' && s.pos+1 < end && isMarkdownPunct(s.peek(1)):
+			// An escaped character, which never starts any markup.
+			s.pos += 2
+			s.emit(Plaintext)
+		case c == '`':
+			lexMarkdownCodeSpan(s)
+		case c == '*' || c == '_' || c == '~':
+			if !acceptMarkdownEmphasis(s, end) {
+				s.acceptWhile(func(r rune) bool { return r == rune(c) })
+				s.emit(Plaintext)
+				continue
+			}
+			s.emit(Literal)
+		case c == '[', c == '!' && s.peek(1) == '[':
+			lexMarkdownLink(s, end)
+		case c == '<' && s.hasPrefix("<!--"):
+			if s.acceptUntil("-->") {
+				s.pos += 3
+			}
+			s.emit(Comment)
+		case c == '<':
+			lexMarkdownAngle(s.lexState)
+		case c == '&' && acceptHTMLEntity(s.lexState):
+			s.emit(Literal)
+		default:
+			s.pos++
+			for s.pos < end && strings.IndexByte("\\`*_~[!<&", s.peek(0)) < 0 {
+				s.pos++
+			}
+			s.emit(Plaintext)
+		}
+		if s.pos > end {
+			// A code span, comment or HTML tag spanned lines, so the contents
+			// continue to the end of the line it ended on.
+			if end = bytes.IndexByte(s.src[s.pos:], '\n'); end < 0 {
+				end = len(s.src)
+			} else {
+				end += s.pos
+			}
+		}
+	}
+}
+
+func isMarkdownPunct(c byte) bool {
+	return strings.IndexByte("!\"#$%&'()*+,-./:;<=>?@[\\]^_`{|}~", c) >= 0
+}
+
+// lexMarkdownCodeSpan lexes a code span, which is delimited by backtick
+// strings of equal length and may not span a blank line.
+func lexMarkdownCodeSpan(s *markdownLexState) {
+	s.acceptWhile(func(r rune) bool { return r == '`' })
+	delim := s.text()
+	if s.pos < s.unclosed[delim] {
+		s.emit(Plaintext) // there is no closer before the end of the block
+		return
+	}
+	for i := s.pos; ; {
+		j := bytes.Index(s.src[i:], []byte(delim))
+		if j < 0 {
+			s.unclosed[delim] = len(s.src)
+			break
+		}
+		if k := bytes.Index(s.src[i:i+j], []byte("\n\n")); k >= 0 {
+			s.unclosed[delim] = i + k // the end of the block
+			break
+		}
+		i += j
+		n := len(delim)
+		for i+n < len(s.src) && s.src[i+n] == '`' {
+			n++
+		}
+		if n == len(delim) {
+			s.pos = i + n
+			s.emit(String)
+			return
+		}
+		i += n
+	}
+	s.emit(Plaintext)
+}
+
+// acceptMarkdownEmphasis advances past emphasized text, such as *em*, **strong**,
+// _em_ or ~~strikethrough~~, within the line, and reports whether there was
+// such text at pos.
+func acceptMarkdownEmphasis(s *markdownLexState, end int) bool {
+	c := s.peek(0)
+	n := 0
+	for s.peek(n) == c {
+		n++
+	}
+	if n > 3 || c == '~' && n != 2 || s.pos+n >= end || isMarkdownSpace(s.peek(n)) {
+		return false
+	}
+	if c == '_' && s.pos > 0 && isMarkdownWordByte(s.src[s.pos-1]) {
+		return false // an underscore inside a word, as in snake_case
+	}
+	delim := s.src[s.pos : s.pos+n]
+	if s.pos < s.unclosed[string(delim)] {
+		return false
+	}
+	i := s.pos + n
+	for ; i < end; i++ {
+		if s.src[i] == '`' {
+			break // a code span binds more tightly
+		}
+		if !bytes.HasPrefix(s.src[i:end], delim) || isMarkdownSpace(s.src[i-1]) {
+			continue
+		}
+		if i+n < end && (s.src[i+n] == c || c == '_' && isMarkdownWordByte(s.src[i+n])) {
+			continue
+		}
+		s.pos = i + n
+		return true
+	}
+	s.unclosed[string(delim)] = i
+	return false
+}
+
+func isMarkdownWordByte(c byte) bool {
+	return isIdentStartByte(c) || isDigit(c) || c >= 0x80
+}
+
+// lexMarkdownLink lexes a link or image, such as [text](url) or
+// ![alt][ref]. Its text is lexed as inline content, and its destination is
+// highlighted as HTMLAttrValue.
+func lexMarkdownLink(s *markdownLexState, end int) {
+	open := s.pos
+	if s.peek(0) == '!' {
+		open++
+	}
+	text := markdownMatchingBracket(s.src[:end], open, '[', ']')
+	dest := -1
+	if text >= 0 && text+1 < end {
+		switch s.src[text+1] {
+		case '(':
+			dest = markdownMatchingBracket(s.src[:end], text+1, '(', ')')
+		case '[':
+			dest = markdownMatchingBracket(s.src[:end], text+1, '[', ']')
+		}
+	}
+	if dest < 0 {
+		s.pos = open + 1
+		s.emit(Plaintext)
+		return
+	}
+	s.pos = open + 1
+	s.emit(Punctuation)
+	lexMarkdownInline(s, text)
+	s.pos = text + 2
+	s.emit(Punctuation)
+
+	if s.src[text+1] == '[' {
+		// A reference to a link definition.
+		s.pos = dest
+		s.emit(Constant)
+	} else {
+		for s.pos < dest && s.peek(0) != ' ' {
+			s.pos++
+		}
+		s.emit(HTMLAttrValue)
+		for s.pos < dest && s.peek(0) == ' ' {
+			s.pos++
+		}
+		s.emit(Whitespace)
+		s.pos = dest
+		s.emit(String) // a title
+	}
+	s.pos++
+	s.emit(Punctuation)
+}
+
+// markdownMatchingBracket returns the index of the bracket that closes the
+// one at open in src, or -1 if there is none.
+func markdownMatchingBracket(src []byte, open int, left, right byte) int {
+	depth := 0
+	for i := open; i < len(src); i++ {
+		switch src[i] {
+		case '\\':
+			i++
+		case left:
+			depth++
+		case right:
+			if depth--; depth == 0 {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+// lexMarkdownAngle lexes an autolink, such as <https://example.com>, or raw
+// HTML, which is delegated to the HTML lexer.
+func lexMarkdownAngle(s *lexState) {
+	gt := bytes.IndexByte(s.src[s.pos:], '>')
+	if gt < 0 {
+		s.pos++
+		s.emit(Plaintext)
+		return
+	}
+	inner := string(s.src[s.pos+1 : s.pos+gt])
+	switch {
+	case !strings.ContainsAny(inner, " \n<") && (strings.Contains(inner, ":") || strings.Contains(inner, "@")):
+		s.pos += gt + 1
+		s.emit(HTMLAttrValue)
+	case len(inner) > 0 && (isHTMLNameStart(inner[0]) || inner[0] == '/'):
+		s.pos += gt + 1
+		s.delegate(htmlLexer{}, Plaintext)
+	default:
+		s.pos++
+		s.emit(Plaintext)
+	}
+}
@@ -0,0 +1,327 @@
+package syntaxhighlight
+
+import (
+	"bytes"
+	"regexp"
+	"strings"
+)
+
+// phpLexer tokenizes PHP files, or, if code is set, PHP code without the
+// tags that delimit it. The text around <?php ... ?> blocks is tokenized by
+// the HTML lexer, and the tags themselves are highlighted as Preprocessor.
+// In PHP code, $variables, including those interpolated into strings and
+// heredocs, are highlighted as Parameter and #[attributes] as Decorator.
+type phpLexer struct {
+	code bool
+}
+
+func init() {
+	Register(LexerConfig{
+		Name:         "php",
+		Aliases:      []string{"phtml"},
+		Filenames:    []string{"*.php", "*.phtml", "*.php3", "*.php4", "*.php5", "*.phps"},
+		MimeTypes:    []string{"application/x-php", "text/x-php"},
+		Interpreters: []string{"php"},
+	}, phpLexer{})
+}
+
+var (
+	phpOperators = []string{
+		"?->", "->", "=>", "::", "===", "!==", "==", "!=", "<>", "<=>", "<=",
+		">=", "&&", "||", "??=", "??", "++", "--", "+=", "-=", "*=", "/=",
+		".=", "%=", "**", "...", "<<", ">>",
+	}
+
+	// phpHeredoc matches the opening of a heredoc, such as <<<EOT or
+	// <<<"EOT", or of a nowdoc, such as <<<'EOT', which ends its line.
+	phpHeredoc = regexp.MustCompile(`^<<<[ \t]*(["']?)([A-Za-z_][A-Za-z0-9_]*)(["']?)\r?\n`)
+)
+
+// phpBlock is a block of PHP code in a PHP file, from its opening tag up to
+// and including its closing tag, if any.
+type phpBlock struct {
+	start, end int
+	toks       []Token
+}
+
+func (l phpLexer) Tokens(src []byte) ([]Token, error) {
+	if l.code {
+		s := newLexState(src)
+		for !s.eof() {
+			lexPHP(s)
+		}
+		return s.toks, nil
+	}
+
+	// Lex the blocks of PHP code first, as only their code tells where they
+	// end, and then the HTML around them with the blocks blanked out, so
+	// that a block in a tag or attribute value doesn't disturb the HTML.
+	var blocks []phpBlock
+	html := []byte(string(src))
+	for i := phpOpenTag(src, 0); i >= 0; {
+		block := &lexState{src: src, start: i, pos: i}
+		lexPHPBlock(block)
+		blocks = append(blocks, phpBlock{i, block.pos, block.toks})
+		for j := i; j < block.pos; j++ {
+			if html[j] != '\n' {
+				html[j] = ' '
+			}
+		}
+		i = phpOpenTag(src, block.pos)
+	}
+	htmlToks, err := htmlLexer{}.Tokens(html)
+	if err != nil {
+		return nil, err
+	}
+
+	s := newLexState(src)
+	for _, tok := range htmlToks {
+		for start, end := tok.Offset, tok.Offset+len(tok.Text); start < end; {
+			if len(blocks) > 0 && start >= blocks[0].start {
+				s.toks = append(s.toks, blocks[0].toks...)
+				s.start, s.pos = blocks[0].end, blocks[0].end
+				start = blocks[0].end
+				blocks = blocks[1:]
+				continue
+			}
+			pieceEnd := end
+			if len(blocks) > 0 && blocks[0].start < pieceEnd {
+				pieceEnd = blocks[0].start
+			}
+			if pieceEnd > s.pos {
+				s.pos = pieceEnd
+				s.emit(tok.Kind)
+			}
+			start = pieceEnd
+		}
+	}
+	return s.toks, nil
+}
+
+func (phpLexer) keywordTable() *keywordTable { return phpKeywords }
+
+// phpOpenTag returns the offset of the first tag at or after i that opens a
+// block of PHP code, <?php, <?= or <?, or -1 if there is none. Processing
+// instructions, such as <?xml, don't open blocks.
+func phpOpenTag(src []byte, i int) int {
+	for {
+		j := bytes.Index(src[i:], []byte("<?"))
+		if j < 0 {
+			return -1
+		}
+		i += j
+		rest := src[i+2:]
+		if len(rest) == 0 || rest[0] == '=' || isPHPSpace(rest[0]) || len(rest) >= 3 && strings.EqualFold(string(rest[:3]), "php") {
+			return i
+		}
+		i += 2
+	}
+}
+
+func isPHPSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\r' || c == '\n'
+}
+
+// lexPHPBlock lexes a block of PHP code, whose opening tag is at pos.
+func lexPHPBlock(s *lexState) {
+	s.pos += 2
+	if !s.acceptByte('=') && s.hasPrefixFold("php") {
+		s.pos += 3
+	}
+	s.emit(Preprocessor)
+	for !s.eof() {
+		if s.hasPrefix("?>") {
+			s.pos += 2
+			s.emit(Preprocessor)
+			return
+		}
+		lexPHP(s)
+	}
+}
+
+// lexPHP lexes a single PHP token.
+func lexPHP(s *lexState) {
+	if s.lexWhitespace() {
+		return
+	}
+	c := s.peek(0)
+	switch {
+	case s.hasPrefix("#["):
+		// An attribute, such as #[Route("/users")].
+		s.pos += 2
+		if s.acceptBalanced('[', ']', `"'`) {
+			s.pos++
+		}
+		s.emit(Decorator)
+	case c == '#' || s.hasPrefix("//"):
+		// A comment, which ends at the end of its line or at a closing tag.
+		for !s.eof() && s.peek(0) != '\n' && !s.hasPrefix("?>") {
+			s.pos++
+		}
+		s.emit(Comment)
+	case s.hasPrefix("/*"):
+		if s.acceptUntil("*/") {
+			s.pos += 2
+		}
+		s.emit(Comment)
+	case c == '$' && (isIdentStartByte(s.peek(1)) || s.peek(1) == '$'):
+		// A variable, or a variable variable, such as $$name.
+		for s.acceptByte('$') {
+		}
+		s.acceptWhile(isIdentPart)
+		if s.text() == "$this" {
+			s.emit(Builtin)
+		} else {
+			s.emit(Parameter)
+		}
+	case c == '\'':
+		s.pos++
+		s.acceptQuoted("'", true, true)
+		s.emit(String)
+	case c == '"' || c == '`':
+		s.pos++
+		lexPHPInterpolated(s, func() bool { return s.peek(0) == c })
+		s.acceptByte(c)
+		s.emit(String)
+	case s.hasPrefix("<<<") && lexPHPHeredoc(s):
+	case s.acceptNumber('_'):
+		s.emit(Decimal)
+	case isIdentStartByte(c) || c == '\\' && isIdentStartByte(s.peek(1)):
+		// A name, which may be qualified, as in \App\Models\User.
+		s.acceptWhile(func(r rune) bool { return isIdentPart(r) || r == '\\' })
+		s.emit(phpIdentKind(s))
+	case s.acceptAny(phpOperators):
+		s.emit(Punctuation)
+	default:
+		_, w := s.peekRune()
+		s.pos += w
+		s.emit(Punctuation)
+	}
+}
+
+// phpIdentKind returns the kind of the name that is the current token.
+func phpIdentKind(s *lexState) Kind {
+	ident := s.text()
+	prev, _ := s.last()
+	call := s.peek(0) == '('
+	switch prev.Text {
+	case "->", "?->":
+		if call {
+			return Method
+		}
+		return Field
+	case "::":
+		switch {
+		case call:
+			return Method
+		case ident == "class":
+			return Keyword
+		}
+		return Constant
+	}
+	if kind, ok := phpKeywords.kind(ident); ok {
+		return kind
+	}
+	if prev.Kind == Keyword {
+		switch strings.ToLower(prev.Text) {
+		case "function", "fn":
+			return Function
+		case "class", "interface", "trait", "enum", "extends", "implements", "new", "instanceof", "insteadof":
+			return Type
+		case "namespace", "use":
+			return Package
+		case "const":
+			return Constant
+		}
+	}
+	if call {
+		return Function
+	}
+	if i := strings.LastIndexByte(ident, '\\'); i >= 0 {
+		ident = ident[i+1:]
+	}
+	return identKindByCase(ident)
+}
+
+// lexPHPInterpolated lexes the contents of a double-quoted string or a
+// heredoc up to the point where atEnd returns true. The variables
+// interpolated into it, such as $name, $user->name or $items[0], are
+// highlighted as Parameter, and {$...} expressions as code.
+func lexPHPInterpolated(s *lexState, atEnd func() bool) {
+	for !s.eof() && !atEnd() {
+		switch {
+		case s.peek(0) == '\\' && s.pos+1 < len(s.src):
+			s.pos += 2
+		case s.peek(0) == '$' && isIdentStartByte(s.peek(1)):
+			s.emit(String)
+			s.pos++
+			s.acceptWhile(isIdentPart)
+			switch {
+			case s.hasPrefix("->") && isIdentStartByte(s.peek(2)):
+				s.pos += 2
+				s.acceptWhile(isIdentPart)
+			case s.peek(0) == '[':
+				if i := bytes.IndexAny(s.src[s.pos:], "]\"\n "); i > 0 && s.src[s.pos+i] == ']' {
+					s.pos += i + 1
+				}
+			}
+			s.emit(Parameter)
+		case s.hasPrefix("{$"):
+			s.emit(String)
+			s.pos++
+			s.emit(Punctuation)
+			s.delegateBalanced('{', '}', `"'`, phpLexer{code: true}, Plaintext)
+			if s.acceptByte('}') {
+				s.emit(Punctuation)
+			}
+		default:
+			s.pos++
+		}
+	}
+}
+
+// lexPHPHeredoc lexes a heredoc or nowdoc, its body and its terminator, and
+// reports whether one starts at pos. Since PHP 7.3, the terminator may be
+// indented and followed by more code on its line.
+func lexPHPHeredoc(s *lexState) bool {
+	m := phpHeredoc.FindSubmatch(s.src[s.pos:])
+	if m == nil || !bytes.Equal(m[1], m[3]) {
+		return false
+	}
+	id := m[2]
+	s.pos += len(bytes.TrimRight(m[0], "\r\n"))
+	s.emit(String)
+	s.acceptByte('\r')
+	s.acceptByte('\n')
+	s.emit(Whitespace)
+
+	bodyEnd, termEnd := len(s.src), len(s.src)
+	for i := s.pos; i < len(s.src); {
+		lineEnd := bytes.IndexByte(s.src[i:], '\n')
+		if lineEnd < 0 {
+			lineEnd = len(s.src)
+		} else {
+			lineEnd += i
+		}
+		line := bytes.TrimLeft(s.src[i:lineEnd], " \t")
+		if bytes.HasPrefix(line, id) && (len(line) == len(id) || !isIdentPart(rune(line[len(id)]))) {
+			bodyEnd, termEnd = i, lineEnd-len(line)+len(id)
+			break
+		}
+		i = lineEnd + 1
+	}
+	// The body is lexed on the source cut at its end, so that an
+	// interpolation that isn't closed doesn't run past it.
+	src := s.src
+	s.src = s.src[:bodyEnd]
+	if string(m[1]) == "'" {
+		s.pos = bodyEnd // a nowdoc, which has no interpolation
+	} else {
+		lexPHPInterpolated(s, func() bool { return false })
+	}
+	s.emit(String)
+	s.src = src
+	s.pos = termEnd
+	s.emit(String)
+	return true
+}
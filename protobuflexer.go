@@ -0,0 +1,153 @@
+package syntaxhighlight
+
+// protobufLexer tokenizes Protocol Buffers definitions. The names declared by
+// message, enum and service declarations and the types of fields are
+// highlighted as Type, rpc methods as Function, fields as Field, enum values
+// as Constant and the names of options as Decorator.
+type protobufLexer struct{}
+
+func init() {
+	Register(LexerConfig{
+		Name:      "protobuf",
+		Aliases:   []string{"proto"},
+		Filenames: []string{"*.proto"},
+		MimeTypes: []string{"text/x-protobuf"},
+	}, protobufLexer{})
+}
+
+// protobufLexState is the state of the Protobuf lexer.
+type protobufLexState struct {
+	*lexState
+	decl     string   // the keyword of the declaration at pos, such as "message"
+	blocks   []string // the keywords of the declarations of the enclosing blocks
+	brackets int      // the depth of lists of field options
+	option   bool     // whether pos is in the name of an option
+}
+
+func (protobufLexer) Tokens(src []byte) ([]Token, error) {
+	s := &protobufLexState{lexState: newLexState(src)}
+	for !s.eof() {
+		lexProtobuf(s)
+	}
+	return s.toks, nil
+}
+
+func (protobufLexer) keywordTable() *keywordTable { return protobufKeywords }
+
+// lexProtobuf lexes a single Protobuf token.
+func lexProtobuf(s *protobufLexState) {
+	if s.lexWhitespace() {
+		return
+	}
+	c := s.peek(0)
+	switch {
+	case s.hasPrefix("//"):
+		s.acceptLine()
+		s.emit(Comment)
+	case s.hasPrefix("/*"):
+		if s.acceptUntil("*/") {
+			s.pos += 2
+		}
+		s.emit(Comment)
+	case c == '"' || c == '\'':
+		s.pos++
+		s.acceptQuoted(string(c), true, false)
+		s.emit(String)
+	case isIdentStartByte(c) || c == '.' && isIdentStartByte(s.peek(1)):
+		// An identifier, or a qualified name, such as google.protobuf.Any.
+		s.pos++
+		s.acceptWhile(func(r rune) bool { return isIdentPart(r) || r == '.' })
+		s.emit(protobufIdentKind(s))
+	case s.acceptNumber(0):
+		s.emit(Decimal)
+	default:
+		_, w := s.peekRune()
+		s.pos += w
+		switch c {
+		case '{':
+			s.blocks = append(s.blocks, s.decl)
+			s.decl = ""
+		case '}':
+			if len(s.blocks) > 0 {
+				s.blocks = s.blocks[:len(s.blocks)-1]
+			}
+			s.decl = ""
+		case '[':
+			s.brackets++
+			s.option = true
+		case ']':
+			s.brackets--
+			s.option = false
+		case ',':
+			s.option = s.brackets > 0
+		case '=', ';':
+			s.option = false
+		}
+		if c == ';' {
+			s.decl = ""
+		}
+		s.emit(Punctuation)
+	}
+}
+
+// protobufIdentKind returns the kind of the identifier that is the current
+// token.
+func protobufIdentKind(s *protobufLexState) Kind {
+	ident := s.text()
+	prev, _ := s.last()
+	next := protobufNextByte(s)
+	if prev.Kind == Keyword {
+		switch prev.Text {
+		case "message", "enum", "service", "extend":
+			return Type
+		case "rpc":
+			return Function
+		case "oneof":
+			return Field
+		case "package":
+			return Package
+		}
+	}
+	switch {
+	case s.option:
+		return Decorator
+	case next == '=' && len(s.blocks) > 0:
+		if s.blocks[len(s.blocks)-1] == "enum" {
+			return Constant
+		}
+		return Field
+	case next == ':':
+		return Field // a field of an aggregate value of an option
+	}
+	if kind, ok := protobufKeywords.kind(ident); ok {
+		switch ident {
+		case "message", "enum", "service", "rpc", "oneof", "extend":
+			s.decl = ident
+		case "option":
+			s.option = true
+		}
+		return kind
+	}
+	switch {
+	case isIdentStartByte(next), next == '.':
+		return Type // the type of a field, whose name follows
+	case prev.Text == "<", prev.Text == "," && next == '>':
+		return Type // the key or value type of a map
+	case prev.Text == "(" && s.decl == "rpc", prev.Text == "stream":
+		return Type // the request or response type of a method
+	}
+	return identKindByCase(ident)
+}
+
+// protobufNextByte returns the first byte after pos that isn't whitespace,
+// or 0 at EOF.
+func protobufNextByte(s *protobufLexState) byte {
+	for i := s.pos; i < len(s.src); i++ {
+		switch s.src[i] {
+		case ' ', '\t', '\r', '\n':
+			continue
+		}
+		return s.src[i]
+	}
+	return 0
+}
@@ -0,0 +1,236 @@
+package syntaxhighlight
+
+import (
+	"bytes"
+	"strings"
+)
+
+// pythonLexer tokenizes Python source code.
+type pythonLexer struct{}
+
+func init() {
+	Register(LexerConfig{
+		Name:         "python",
+		Aliases:      []string{"py", "python3"},
+		Filenames:    []string{"*.py", "*.pyw", "*.pyi", "SConstruct", "SConscript"},
+		MimeTypes:    []string{"text/x-python", "application/x-python"},
+		Interpreters: []string{"python", "pypy"},
+	}, pythonLexer{})
+}
+
+var pythonOperators = []string{
+	"**=", "//=", ">>=", "<<=", "...", "->", ":=", "==", "!=", "<=", ">=",
+	"**", "//", "<<", ">>", "+=", "-=", "*=", "/=", "%=", "&=", "|=", "^=",
+	"@=",
+}
+
+func (pythonLexer) Tokens(src []byte) ([]Token, error) {
+	s := newLexState(src)
+	for !s.eof() {
+		lexPython(s)
+	}
+	return s.toks, nil
+}
+
+func (pythonLexer) keywordTable() *keywordTable { return pythonKeywords }
+
+// lexPython lexes a single Python token.
+func lexPython(s *lexState) {
+	if s.lexWhitespace() {
+		return
+	}
+	c := s.peek(0)
+	switch {
+	case c == '#':
+		s.acceptLine()
+		s.emit(Comment)
+	case c == '@' && s.atLineStart() && isIdentStartByte(s.peek(1)):
+		s.pos++
+		s.acceptWhile(func(r rune) bool { return isIdentPart(r) || r == '.' })
+		s.emit(Decorator)
+	case c == '"' || c == '\'':
+		lexPythonString(s, "")
+	case s.acceptNumber('_'):
+		s.emit(Decimal)
+	case isIdentStartByte(c) || c >= 0x80:
+		r, w := s.peekRune()
+		if !isIdentStart(r) {
+			s.pos += w
+			s.emit(Punctuation)
+			return
+		}
+		s.acceptWhile(isIdentPart)
+		if q := s.peek(0); (q == '"' || q == '\'') && isPythonStringPrefix(s.text()) {
+			lexPythonString(s, s.text())
+			return
+		}
+		s.emit(pythonIdentKind(s))
+	case s.acceptAny(pythonOperators):
+		s.emit(Punctuation)
+	default:
+		_, w := s.peekRune()
+		s.pos += w
+		s.emit(Punctuation)
+	}
+}
+
+// isPythonStringPrefix reports whether prefix is a valid string prefix, such
+// as "r", "b", "f" or "rb", in any case.
+func isPythonStringPrefix(prefix string) bool {
+	switch strings.ToLower(prefix) {
+	case "r", "u", "b", "f", "br", "rb", "fr", "rf", "t", "tr", "rt":
+		return true
+	}
+	return false
+}
+
+// pythonIdentKind returns the kind of the identifier that is the current
+// token.
+func pythonIdentKind(s *lexState) Kind {
+	ident := s.text()
+	switch ident {
+	case "match", "case", "type":
+		if isPythonSoftKeyword(s, ident) {
+			return Keyword
+		}
+		if ident == "type" {
+			return Type
+		}
+		return Plaintext
+	}
+	prev, _ := s.last()
+	if prev.Text == "." {
+		// An attribute, which may well be named like a builtin.
+		return identKindByCase(ident)
+	}
+	if kind, ok := pythonKeywords.kind(ident); ok {
+		return kind
+	}
+	if prev.Kind == Keyword {
+		switch prev.Text {
+		case "def":
+			return Function
+		case "class":
+			return Type
+		}
+	}
+	return identKindByCase(ident)
+}
+
+// isPythonSoftKeyword reports whether the identifier ident (one of "match",
+// "case" and "type"), which is the current token, is used as a keyword.
+// These are keywords only at the start of a statement, and only when the
+// statement isn't an expression using a variable of the same name.
+func isPythonSoftKeyword(s *lexState, ident string) bool {
+	if !s.atLineStart() {
+		return false
+	}
+	line := s.src[s.pos:]
+	if i := bytes.IndexByte(line, '\n'); i >= 0 {
+		line = line[:i]
+	}
+	rest := string(line)
+	if i := strings.IndexByte(rest, '#'); i >= 0 {
+		rest = rest[:i]
+	}
+	rest = strings.TrimSpace(rest)
+	if rest == "" || strings.IndexAny(rest[:1], "=.,)]:") >= 0 {
+		return false
+	}
+	if ident == "type" {
+		// type X = ... or type X[T] = ...
+		return isIdentStartByte(rest[0]) && strings.Contains(rest, "=")
+	}
+	return strings.HasSuffix(rest, ":")
+}
+
+// lexPythonString lexes a string literal. The prefix has been consumed
+// already; the quote is at pos.
+func lexPythonString(s *lexState, prefix string) {
+	quote := string(s.peek(0))
+	if s.hasPrefix(quote + quote + quote) {
+		quote = quote + quote + quote
+	}
+	s.pos += len(quote)
+	multiline := len(quote) == 3
+
+	prefix = strings.ToLower(prefix)
+	if !strings.ContainsAny(prefix, "ft") {
+		s.acceptQuoted(quote, true, multiline)
+		s.emit(String)
+		return
+	}
+
+	// f-strings (and t-strings) contain replacement fields, {expr!r:spec},
+	// whose expressions are highlighted as code.
+	for !s.eof() {
+		switch {
+		case s.hasPrefix(quote):
+			s.pos += len(quote)
+			s.emit(String)
+			return
+		case !multiline && s.peek(0) == '\n':
+			s.emit(String)
+			return
+		case s.peek(0) == '\\' && s.pos+1 < len(s.src) && s.peek(1) != '{':
+			s.pos += 2
+		case s.hasPrefix("{{"), s.hasPrefix("}}"):
+			s.pos += 2
+		case s.peek(0) == '{':
+			s.emit(String)
+			lexPythonReplacementField(s, quote, multiline)
+		default:
+			s.pos++
+		}
+	}
+	s.emit(String)
+}
+
+// lexPythonReplacementField lexes an f-string replacement field. The
+// opening brace is at pos.
+func lexPythonReplacementField(s *lexState, quote string, multiline bool) {
+	s.pos++
+	s.emit(Punctuation)
+
+	// The expression ends at a "!" conversion, a ":" format spec or the
+	// closing brace, unless they are nested in brackets.
+	depth := 0
+loop:
+	for !s.eof() && !s.hasPrefix(quote) {
+		c := s.peek(0)
+		switch {
+		case depth == 0 && (c == '}' || c == ':' || c == '!' && s.peek(1) != '='):
+			break loop
+		case c == '\n' && !multiline:
+			break loop
+		case c == '(' || c == '[' || c == '{':
+			depth++
+		case c == ')' || c == ']' || c == '}':
+			depth--
+		case (c == '"' || c == '\'') && c != quote[0]:
+			s.pos++
+			s.acceptQuoted(string(c), true, false)
+			continue
+		}
+		s.pos++
+	}
+	s.delegate(pythonLexer{}, Plaintext)
+
+	if s.peek(0) == '!' {
+		s.pos++
+		s.acceptWhile(isIdentPart)
+		s.emit(Punctuation)
+	}
+	if s.peek(0) == ':' {
+		s.pos++
+		s.emit(Punctuation)
+		for !s.eof() && s.peek(0) != '}' && !s.hasPrefix(quote) && (multiline || s.peek(0) != '\n') {
+			s.pos++
+		}
+		s.emit(String)
+	}
+	if s.peek(0) == '}' {
+		s.pos++
+		s.emit(Punctuation)
+	}
+}
@@ -0,0 +1,146 @@
+package syntaxhighlight
+
+import (
+	"mime"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// LexerConfig describes the language handled by a registered Lexer.
+type LexerConfig struct {
+	// Name is the canonical, lowercase name of the language (e.g. "go").
+	Name string
+
+	// Aliases are alternative names the language is known by (e.g. "golang").
+	Aliases []string
+
+	// Filenames are shell patterns matched against the base name of a file,
+	// either exact names ("Makefile") or globs ("*.go").
+	Filenames []string
+
+	// MimeTypes are the MIME types of source files in the language.
+	MimeTypes []string
+
+	// Interpreters are the names of programs that run scripts in the
+	// language, as they appear on "#!" lines (e.g. "python3"). Trailing
+	// version numbers are ignored when matching.
+	Interpreters []string
+}
+
+type registration struct {
+	config LexerConfig
+	lexer  Lexer
+}
+
+var (
+	registryMu sync.RWMutex
+	registry   []*registration
+	byName     = make(map[string]*registration)
+)
+
+// Register makes a lexer available under the name, aliases, filename
+// patterns and MIME types in config. If Register is called twice with the
+// same name or alias, or if lexer is nil, it panics.
+func Register(config LexerConfig, lexer Lexer) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	if lexer == nil {
+		panic("syntaxhighlight: Register lexer is nil")
+	}
+	r := &registration{config: config, lexer: lexer}
+	for _, name := range append([]string{config.Name}, config.Aliases...) {
+		name = strings.ToLower(name)
+		if _, dup := byName[name]; dup {
+			panic("syntaxhighlight: Register called twice for lexer " + name)
+		}
+		byName[name] = r
+	}
+	registry = append(registry, r)
+}
+
+// Languages returns the configs of all registered lexers, in the order they
+// were registered.
+func Languages() []LexerConfig {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	configs := make([]LexerConfig, len(registry))
+	for i, r := range registry {
+		configs[i] = r.config
+	}
+	return configs
+}
+
+// Lookup returns the lexer registered under the given name or alias
+// (case-insensitively), or nil if there is none.
+func Lookup(name string) Lexer {
+	if r := lookupName(name); r != nil {
+		return r.lexer
+	}
+	return nil
+}
+
+// LookupFilename returns the lexer whose filename patterns match the base
+// name of filename, or nil if there is none. Exact names take precedence over
+// globs, and longer globs over shorter ones.
+func LookupFilename(filename string) Lexer {
+	if r := lookupFilename(filename); r != nil {
+		return r.lexer
+	}
+	return nil
+}
+
+// LookupMimeType returns the lexer registered for mimeType, or nil if there
+// is none. MIME type parameters such as charset are ignored.
+func LookupMimeType(mimeType string) Lexer {
+	if r := lookupMimeType(mimeType); r != nil {
+		return r.lexer
+	}
+	return nil
+}
+
+func lookupName(name string) *registration {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	return byName[strings.ToLower(name)]
+}
+
+func lookupFilename(filename string) *registration {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	base := filepath.Base(filename)
+	var best *registration
+	bestLen := -1
+	for _, r := range registry {
+		for _, pattern := range r.config.Filenames {
+			if !strings.ContainsAny(pattern, "*?[") {
+				if pattern == base {
+					return r
+				}
+				continue
+			}
+			if ok, _ := filepath.Match(pattern, base); ok && len(pattern) > bestLen {
+				best, bestLen = r, len(pattern)
+			}
+		}
+	}
+	return best
+}
+
+func lookupMimeType(mimeType string) *registration {
+	if mt, _, err := mime.ParseMediaType(mimeType); err == nil {
+		mimeType = mt
+	}
+
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	for _, r := range registry {
+		for _, mt := range r.config.MimeTypes {
+			if strings.EqualFold(mt, mimeType) {
+				return r
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,41 @@
+package syntaxhighlight
+
+import "testing"
+
+func TestLookup(t *testing.T) {
+	tests := []struct {
+		lookup func(string) Lexer
+		arg    string
+		want   string
+	}{
+		{Lookup, "go", "go"},
+		{Lookup, "Golang", "go"},
+		{Lookup, "no-such-language", ""},
+		{LookupFilename, "foo/bar.go", "go"},
+		{LookupFilename, "Rakefile", "ruby"},
+		{LookupFilename, "README", ""},
+		{LookupMimeType, "text/x-python; charset=utf-8", "python"},
+		{LookupMimeType, "image/png", ""},
+	}
+	for _, test := range tests {
+		got := test.lookup(test.arg)
+		if test.want == "" {
+			if got != nil {
+				t.Errorf("%q: got lexer %T, want none", test.arg, got)
+			}
+			continue
+		}
+		if want := Lookup(test.want); got == nil || got != want {
+			t.Errorf("%q: got lexer %v, want lexer for %q", test.arg, got, test.want)
+		}
+	}
+}
+
+func TestRegisterDuplicate(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("Register with a duplicate name did not panic")
+		}
+	}()
+	Register(LexerConfig{Name: "GO"}, FallbackLexer)
+}
@@ -0,0 +1,354 @@
+package syntaxhighlight
+
+import (
+	"bytes"
+	"strings"
+)
+
+// rubyLexer tokenizes Ruby source code.
+type rubyLexer struct{}
+
+func init() {
+	Register(LexerConfig{
+		Name:         "ruby",
+		Aliases:      []string{"rb"},
+		Filenames:    []string{"*.rb", "*.rake", "*.gemspec", "*.ru", "Rakefile", "Gemfile", "Guardfile", "Vagrantfile"},
+		MimeTypes:    []string{"text/x-ruby", "application/x-ruby"},
+		Interpreters: []string{"ruby", "jruby"},
+	}, rubyLexer{})
+}
+
+var rubyOperators = []string{
+	"**=", "<=>", "===", "...", "<<=", ">>=", "||=", "&&=", "..", "::",
+	"=>", "->", "==", "!=", "=~", "!~", "<=", ">=", "&&", "||", "<<", ">>",
+	"**", "+=", "-=", "*=", "/=", "%=", "|=", "&=", "^=", "&.",
+}
+
+// rubyHeredoc is a heredoc whose body starts on the line after the one it
+// is opened on.
+type rubyHeredoc struct {
+	id          string
+	indented    bool // <<- and <<~ heredocs may indent the terminator
+	interpolate bool
+}
+
+// rubyLexState is the state of the Ruby lexer: the heredocs opened on the
+// current line, whose bodies follow the next newline.
+type rubyLexState struct {
+	*lexState
+	heredocs []rubyHeredoc
+}
+
+func (rubyLexer) Tokens(src []byte) ([]Token, error) {
+	s := &rubyLexState{lexState: newLexState(src)}
+	for !s.eof() {
+		lexRuby(s)
+	}
+	return s.toks, nil
+}
+
+func (rubyLexer) keywordTable() *keywordTable { return rubyKeywords }
+
+// lexRuby lexes a single Ruby token.
+func lexRuby(s *rubyLexState) {
+	c := s.peek(0)
+	switch {
+	case c == '\n':
+		s.pos++
+		s.emit(Whitespace)
+		for _, h := range s.heredocs {
+			lexRubyHeredocBody(s, h)
+		}
+		s.heredocs = nil
+	case c == ' ' || c == '\t' || c == '\r' || c == '\f' || c == '\v':
+		s.acceptWhile(func(r rune) bool { return r == ' ' || r == '\t' || r == '\r' || r == '\f' || r == '\v' })
+		s.emit(Whitespace)
+	case c == '#':
+		s.acceptLine()
+		s.emit(Comment)
+	case c == '=' && s.hasPrefix("=begin") && s.atLineStart():
+		if !s.acceptUntil("\n=end") {
+			s.emit(Comment)
+			return
+		}
+		s.pos += len("\n=end")
+		s.acceptLine()
+		s.emit(Comment)
+	case c == '_' && s.hasPrefix("__END__") && s.atLineStart():
+		s.pos = len(s.src)
+		s.emit(Comment)
+	case c == '"' || c == '`':
+		s.pos++
+		lexRubyContent(s.lexState, true, String, func() bool { return s.peek(0) == c })
+		s.acceptByte(c)
+		s.emit(String)
+	case c == '\'':
+		s.pos++
+		s.acceptQuoted("'", true, true)
+		s.emit(String)
+	case c == '@':
+		s.pos++
+		if s.peek(0) == '@' {
+			s.pos++
+		}
+		s.acceptWhile(isIdentPart)
+		s.emit(Field)
+	case c == '$':
+		s.pos++
+		if !s.acceptWhile(isIdentPart) && strings.IndexByte("!@;,/\\.~=*$?:\"<>&`'+", s.peek(0)) >= 0 {
+			s.pos++ // special globals like $! and $;
+		}
+		s.emit(Builtin)
+	case c == ':' && s.peek(1) == '"':
+		s.pos += 2
+		lexRubyContent(s.lexState, true, Literal, func() bool { return s.peek(0) == '"' })
+		s.acceptByte('"')
+		s.emit(Literal)
+	case c == ':' && s.peek(1) != ':' && (isIdentStartByte(s.peek(1)) || s.peek(1) >= 0x80) && !rubyAfterIdent(s):
+		s.pos++
+		s.acceptWhile(isIdentPart)
+		if q := s.peek(0); (q == '?' || q == '!' || q == '=') && s.peek(1) != '=' && s.peek(1) != '>' {
+			s.pos++
+		}
+		s.emit(Literal)
+	case c == '/' && rubyExpectsOperand(s):
+		s.pos++
+		lexRubyContent(s.lexState, true, String, func() bool { return s.peek(0) == '/' || s.peek(0) == '\n' })
+		s.acceptByte('/')
+		s.acceptWhile(func(r rune) bool { return strings.ContainsRune("imxounse", r) })
+		s.emit(String)
+	case c == '%' && rubyExpectsOperand(s) && lexRubyPercentLiteral(s):
+	case c == '<' && s.hasPrefix("<<") && lexRubyHeredocStart(s):
+	case s.acceptNumber('_'):
+		s.emit(Decimal)
+	case isIdentStartByte(c) || c >= 0x80:
+		r, w := s.peekRune()
+		if !isIdentStart(r) {
+			s.pos += w
+			s.emit(Punctuation)
+			return
+		}
+		s.acceptWhile(isIdentPart)
+		if q := s.peek(0); (q == '?' || q == '!') && s.peek(1) != '=' {
+			s.pos++
+		}
+		if s.peek(0) == ':' && s.peek(1) != ':' && s.text() != "else" {
+			if prev, _ := s.last(); prev.Text != "?" {
+				// A symbol hash key, as in {name: value}.
+				s.pos++
+				s.emit(Literal)
+				return
+			}
+		}
+		s.emit(rubyIdentKind(s))
+	case s.acceptAny(rubyOperators):
+		s.emit(Punctuation)
+	default:
+		_, w := s.peekRune()
+		s.pos += w
+		s.emit(Punctuation)
+	}
+}
+
+// rubyAfterIdent reports whether the current token directly follows an
+// identifier or closing bracket, as the ":" of a ternary "a ?b:c" does.
+func rubyAfterIdent(s *rubyLexState) bool {
+	if s.start == 0 {
+		return false
+	}
+	c := s.src[s.start-1]
+	return isIdentStartByte(c) || isDigit(c) || c == ')' || c == ']'
+}
+
+// rubyExpectsOperand reports whether an operand rather than an operator may
+// start at the current token, which tells a regexp from a division and a
+// percent literal from a modulo.
+func rubyExpectsOperand(s *rubyLexState) bool {
+	prev, ok := s.last()
+	if !ok {
+		return true
+	}
+	switch prev.Kind {
+	case Punctuation:
+		return prev.Text != ")" && prev.Text != "]" && prev.Text != "}"
+	case Keyword:
+		return prev.Text != "end" && prev.Text != "self"
+	case Plaintext, Builtin, Function:
+		// A method called without parentheses, as in `puts /x/`.
+		next := s.peek(1)
+		return s.start > 0 && s.src[s.start-1] == ' ' && next != ' ' && next != '='
+	}
+	return false
+}
+
+// rubyIdentKind returns the kind of the identifier that is the current
+// token.
+func rubyIdentKind(s *rubyLexState) Kind {
+	ident := s.text()
+	prev := s.lastN(3)
+	if len(prev) == 3 && prev[0].Text == "def" && prev[1].Text == "self" && prev[2].Text == "." {
+		return Function // def self.name
+	}
+	if len(prev) > 0 {
+		switch last := prev[len(prev)-1]; {
+		case last.Text == "." || last.Text == "&.":
+			return identKindByCase(ident)
+		case last.Kind == Keyword && last.Text == "def" && ident != "self":
+			return Function
+		}
+	}
+	if kind, ok := rubyKeywords.kind(ident); ok {
+		return kind
+	}
+	return identKindByCase(ident)
+}
+
+// rubyPercentDelimiters maps the opening delimiters of percent literals that
+// nest to their closing delimiters.
+var rubyPercentDelimiters = map[byte]byte{'(': ')', '[': ']', '{': '}', '<': '>'}
+
+// lexRubyPercentLiteral lexes a percent literal such as %w[a b] or %q{str},
+// and reports whether there was one at pos.
+func lexRubyPercentLiteral(s *rubyLexState) bool {
+	i := 1
+	typ := s.peek(1)
+	if strings.IndexByte("wWiIqQrsx", typ) >= 0 {
+		i++
+	} else {
+		typ = 'Q'
+	}
+	open := s.peek(i)
+	if open == 0 || open == ' ' || open == '\n' || open == '=' || isIdentStartByte(open) || isDigit(open) || open >= 0x80 {
+		return false
+	}
+	close, nests := rubyPercentDelimiters[open]
+	if !nests {
+		close = open
+	}
+
+	kind := String
+	if typ == 'i' || typ == 'I' || typ == 's' {
+		kind = Literal
+	}
+	interpolate := strings.IndexByte("WIQrx", typ) >= 0
+
+	s.pos += i + 1
+	depth := 0
+	lexRubyContent(s.lexState, interpolate, kind, func() bool {
+		switch c := s.peek(0); {
+		case nests && c == open:
+			depth++
+		case c == close && depth > 0:
+			depth--
+		case c == close:
+			return true
+		}
+		return false
+	})
+	s.acceptByte(close)
+	if typ == 'r' {
+		s.acceptWhile(func(r rune) bool { return strings.ContainsRune("imxounse", r) })
+	}
+	s.emit(kind)
+	return true
+}
+
+// lexRubyHeredocStart lexes the opening of a heredoc, such as <<~EOS or
+// <<-'EOS', and reports whether there was one at pos.
+func lexRubyHeredocStart(s *rubyLexState) bool {
+	i := 2
+	indented := false
+	if c := s.peek(i); c == '~' || c == '-' {
+		indented = true
+		i++
+	}
+
+	var h rubyHeredoc
+	switch q := s.peek(i); {
+	case q == '\'' || q == '"' || q == '`':
+		end := bytes.IndexByte(s.src[s.pos+i+1:], q)
+		if end < 0 || bytes.IndexByte(s.src[s.pos+i+1:s.pos+i+1+end], '\n') >= 0 {
+			return false
+		}
+		h = rubyHeredoc{
+			id:          string(s.src[s.pos+i+1 : s.pos+i+1+end]),
+			interpolate: q != '\'',
+		}
+		i += end + 2
+	case isIdentStartByte(q):
+		if !indented && !(rubyExpectsOperand(s) && 'A' <= q && q <= 'Z') {
+			return false
+		}
+		j := i
+		for isIdentStartByte(s.peek(j)) || isDigit(s.peek(j)) {
+			j++
+		}
+		h = rubyHeredoc{id: string(s.src[s.pos+i : s.pos+j]), interpolate: true}
+		i = j
+	default:
+		return false
+	}
+	h.indented = indented
+
+	s.pos += i
+	s.emit(String)
+	s.heredocs = append(s.heredocs, h)
+	return true
+}
+
+// lexRubyHeredocBody lexes the body and the terminator of a heredoc, which
+// start at pos.
+func lexRubyHeredocBody(s *rubyLexState, h rubyHeredoc) {
+	bodyEnd := len(s.src)
+	termEnd := len(s.src)
+	for i := s.pos; i < len(s.src); {
+		lineEnd := bytes.IndexByte(s.src[i:], '\n')
+		if lineEnd < 0 {
+			lineEnd = len(s.src)
+		} else {
+			lineEnd += i
+		}
+		line := bytes.TrimRight(s.src[i:lineEnd], "\r")
+		if h.indented {
+			line = bytes.TrimLeft(line, " \t")
+		}
+		if string(line) == h.id {
+			bodyEnd, termEnd = i, lineEnd
+			break
+		}
+		i = lineEnd + 1
+	}
+
+	// The body is lexed on the source cut at its end, so that an
+	// interpolation that isn't closed doesn't run past it.
+	src := s.src
+	s.src = s.src[:bodyEnd]
+	lexRubyContent(s.lexState, h.interpolate, String, func() bool { return false })
+	s.emit(String)
+	s.src = src
+	s.pos = termEnd
+	s.emit(String)
+}
+
+// lexRubyContent lexes the contents of a string-like literal up to the point
+// where atEnd returns true. If interpolate is set, #{} interpolations are
+// highlighted as code; the rest is of the given kind.
+func lexRubyContent(s *lexState, interpolate bool, kind Kind, atEnd func() bool) {
+	for !s.eof() && !atEnd() {
+		switch {
+		case s.peek(0) == '\\' && s.pos+1 < len(s.src):
+			s.pos += 2
+		case interpolate && s.hasPrefix("#{"):
+			s.emit(kind)
+			s.pos += 2
+			s.emit(Punctuation)
+			s.delegateBalanced('{', '}', "\"'`", rubyLexer{}, Plaintext)
+			if s.peek(0) == '}' {
+				s.pos++
+				s.emit(Punctuation)
+			}
+		default:
+			s.pos++
+		}
+	}
+}
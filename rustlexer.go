@@ -0,0 +1,188 @@
+package syntaxhighlight
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// rustLexer tokenizes Rust source code.
+type rustLexer struct{}
+
+func init() {
+	Register(LexerConfig{
+		Name:      "rust",
+		Aliases:   []string{"rs"},
+		Filenames: []string{"*.rs"},
+		MimeTypes: []string{"text/rust", "text/x-rust"},
+	}, rustLexer{})
+}
+
+var rustOperators = []string{
+	"<<=", ">>=", "...", "..=", "::", "->", "=>", "..", "==", "!=", "<=",
+	">=", "&&", "||", "+=", "-=", "*=", "/=", "%=", "^=", "&=", "|=", "<<",
+	">>",
+}
+
+func (rustLexer) Tokens(src []byte) ([]Token, error) {
+	s := newLexState(src)
+	if s.hasPrefix("#!") && !s.hasPrefix("#![") {
+		s.acceptLine()
+		s.emit(Comment)
+	}
+	for !s.eof() {
+		lexRust(s)
+	}
+	return s.toks, nil
+}
+
+func (rustLexer) keywordTable() *keywordTable { return rustKeywords }
+
+// lexRust lexes a single Rust token.
+func lexRust(s *lexState) {
+	if s.lexWhitespace() {
+		return
+	}
+	c := s.peek(0)
+	switch {
+	case s.hasPrefix("//"):
+		s.acceptLine()
+		s.emit(Comment)
+	case s.hasPrefix("/*"):
+		s.acceptNested("/*", "*/")
+		s.emit(Comment)
+	case s.hasPrefix("#[") || s.hasPrefix("#!["):
+		// An attribute, such as #[derive(Debug)] or #![allow(dead_code)].
+		s.pos += 2
+		s.acceptByte('[')
+		if s.acceptBalanced('[', ']', `"`) {
+			s.pos++
+		}
+		s.emit(Decorator)
+	case c == '"':
+		lexRustString(s, "")
+	case c == '\'':
+		lexRustQuote(s)
+	case c == '.':
+		// Rust has no floats with a leading dot, so ".0" is a tuple field.
+		if !s.acceptAny(rustOperators) {
+			s.pos++
+		}
+		s.emit(Punctuation)
+	case s.acceptNumber('_'):
+		s.emit(Decimal)
+	case isIdentStartByte(c) || c >= 0x80:
+		r, w := s.peekRune()
+		if !isIdentStart(r) {
+			s.pos += w
+			s.emit(Punctuation)
+			return
+		}
+		s.acceptWhile(isIdentPart)
+		ident := s.text()
+		switch q := s.peek(0); {
+		case q == '#' && ident == "r" && isIdentStartByte(s.peek(1)):
+			// A raw identifier, such as r#type.
+			s.pos++
+			s.acceptWhile(isIdentPart)
+			s.emit(Plaintext)
+			return
+		case (q == '"' || q == '\'' || q == '#') && isRustStringPrefix(ident, q):
+			lexRustString(s, ident)
+			return
+		case q == '!' && s.peek(1) != '=':
+			// A macro invocation, such as println!(...), or macro_rules!.
+			s.pos++
+			if ident == "macro_rules" {
+				s.emit(Keyword)
+			} else {
+				s.emit(Function)
+			}
+			return
+		}
+		s.emit(rustIdentKind(s))
+	case s.acceptAny(rustOperators):
+		s.emit(Punctuation)
+	default:
+		_, w := s.peekRune()
+		s.pos += w
+		s.emit(Punctuation)
+	}
+}
+
+// isRustStringPrefix reports whether prefix is the prefix of a string, byte
+// or raw string literal whose next character (a quote or "#") is next.
+func isRustStringPrefix(prefix string, next byte) bool {
+	switch prefix {
+	case "b":
+		return next != '#'
+	case "c":
+		return next == '"'
+	case "r", "br", "cr":
+		return next != '\''
+	}
+	return false
+}
+
+// lexRustString lexes a string literal, whose prefix has already been
+// consumed. The opening quote (or, for raw strings, the first "#") is at pos.
+func lexRustString(s *lexState, prefix string) {
+	if strings.HasSuffix(prefix, "r") {
+		// A raw string, r#"..."#, with any number of "#".
+		hashes := 0
+		for s.peek(hashes) == '#' {
+			hashes++
+		}
+		if s.peek(hashes) != '"' {
+			s.pos += hashes
+			s.emit(Punctuation)
+			return
+		}
+		s.pos += hashes + 1
+		s.acceptQuoted(`"`+strings.Repeat("#", hashes), false, true)
+		s.emit(String)
+		return
+	}
+	quote := s.peek(0)
+	s.pos++
+	s.acceptQuoted(string(quote), true, quote == '"')
+	s.emit(String)
+}
+
+// lexRustQuote lexes a character literal or a lifetime, such as 'a', which
+// the "'" at pos starts.
+func lexRustQuote(s *lexState) {
+	s.pos++
+	if s.peek(0) != '\\' {
+		_, w := utf8.DecodeRune(s.src[s.pos:])
+		if s.peek(w) != '\'' && isIdentStartByte(s.peek(0)) {
+			s.acceptWhile(isIdentPart)
+			s.emit(TypeParameter)
+			return
+		}
+	}
+	s.acceptQuoted("'", true, false)
+	s.emit(String)
+}
+
+// rustIdentKind returns the kind of the identifier that is the current token.
+func rustIdentKind(s *lexState) Kind {
+	ident := s.text()
+	prev, _ := s.last()
+	if prev.Text == "." {
+		return identKindByCase(ident)
+	}
+	if kind, ok := rustKeywords.kind(ident); ok {
+		return kind
+	}
+	if prev.Kind == Keyword {
+		switch prev.Text {
+		case "fn", "macro_rules!":
+			return Function
+		case "struct", "enum", "trait", "type", "union":
+			return Type
+		case "mod", "crate":
+			return Package
+		}
+	}
+	return identKindByCase(ident)
+}
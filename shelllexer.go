@@ -0,0 +1,376 @@
+package syntaxhighlight
+
+import (
+	"bytes"
+	"regexp"
+	"strings"
+)
+
+// shellLexer tokenizes POSIX shell and Bash scripts.
+type shellLexer struct{}
+
+func init() {
+	Register(LexerConfig{
+		Name:         "bash",
+		Aliases:      []string{"sh", "shell", "zsh", "ksh"},
+		Filenames:    []string{"*.sh", "*.bash", "*.zsh", "*.ksh", ".bashrc", ".bash_profile", ".bash_aliases", ".profile", ".zshrc", ".zprofile", "PKGBUILD"},
+		MimeTypes:    []string{"application/x-sh", "application/x-shellscript", "text/x-shellscript"},
+		Interpreters: []string{"sh", "bash", "zsh", "ksh", "dash", "ash"},
+	}, shellLexer{})
+}
+
+var shellOperators = []string{
+	";;&", "&>>", "<<<", "&&", "||", ";;", ";&", "|&", ">>", "<&", ">&",
+	"&>", ">|", "<>", "<<", "|", "&", ";", "(", ")", "<", ">",
+}
+
+// shellHeredoc is a heredoc whose body starts on the line after the one it
+// is opened on.
+type shellHeredoc struct {
+	id        string
+	stripTabs bool // <<- heredocs may indent the terminator with tabs
+	expand    bool // heredocs with an unquoted delimiter undergo expansion
+}
+
+// shellLexState is the state of the shell lexer: the heredocs opened on the
+// current line, whose bodies follow the next newline.
+type shellLexState struct {
+	*lexState
+	heredocs []shellHeredoc
+}
+
+func (shellLexer) Tokens(src []byte) ([]Token, error) {
+	s := &shellLexState{lexState: newLexState(src)}
+	for !s.eof() {
+		lexShell(s)
+	}
+	return s.toks, nil
+}
+
+func (shellLexer) keywordTable() *keywordTable { return shellKeywords }
+
+// lexShell lexes a single shell token.
+func lexShell(s *shellLexState) {
+	c := s.peek(0)
+	switch {
+	case c == '\n':
+		s.pos++
+		s.emit(Whitespace)
+		for _, h := range s.heredocs {
+			lexShellHeredocBody(s, h)
+		}
+		s.heredocs = nil
+	case c == ' ' || c == '\t' || c == '\r':
+		s.acceptWhile(func(r rune) bool { return r == ' ' || r == '\t' || r == '\r' })
+		s.emit(Whitespace)
+	case c == '#' && shellWordStart(s.lexState):
+		s.acceptLine()
+		s.emit(Comment)
+	case c == '\'':
+		s.pos++
+		s.acceptQuoted("'", false, true)
+		s.emit(String)
+	case c == '"':
+		lexShellDoubleQuoted(s.lexState)
+	case c == '$':
+		if !lexShellExpansion(s.lexState) {
+			s.pos++
+			s.emit(Plaintext)
+		}
+	case c == '`':
+		lexShellBackquoted(s.lexState)
+	case s.hasPrefix("<<") && !s.hasPrefix("<<<") && lexShellHeredocStart(s):
+	case s.acceptAny(shellOperators):
+		s.emit(Punctuation)
+	default:
+		lexShellWord(s)
+	}
+}
+
+// shellWordStart reports whether the current token starts a word, which a
+// "#" must do to start a comment.
+func shellWordStart(s *lexState) bool {
+	return s.start == 0 || strings.IndexByte(" \t\r\n;|&()", s.src[s.start-1]) >= 0
+}
+
+// isShellWordPart reports whether c may be part of an unquoted word.
+func isShellWordPart(c byte) bool {
+	return strings.IndexByte(" \t\r\n|&;()<>\"'`$", c) < 0
+}
+
+// shellAssignment matches the start of a variable assignment, such as
+// "PATH=" or "args+=".
+var shellAssignment = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*(\[[^\]]*\])?\+?=`)
+
+// lexShellWord lexes an unquoted word, or the part of one up to a quote or
+// expansion.
+func lexShellWord(s *shellLexState) {
+	for !s.eof() && isShellWordPart(s.peek(0)) {
+		if s.peek(0) == '\\' {
+			s.pos++
+		}
+		s.pos++
+	}
+	if s.pos > len(s.src) {
+		s.pos = len(s.src)
+	}
+	word := s.text()
+
+	if m := shellAssignment.FindString(word); m != "" && shellWordStart(s.lexState) {
+		end := s.pos
+		name := strings.TrimRight(m, "+=")
+		s.pos = s.start + len(name)
+		s.emit(Parameter)
+		s.pos = s.start + len(m) - len(name)
+		s.emit(Punctuation)
+		s.pos = end
+		s.emit(Plaintext)
+		return
+	}
+	s.emit(shellWordKind(s, word))
+}
+
+// shellWordKind returns the kind of the word that was just lexed: a keyword
+// or builtin at the start of a command, a function name, or plain text.
+func shellWordKind(s *shellLexState, word string) Kind {
+	prev, _ := s.last()
+	switch {
+	case prev.Kind == Keyword && prev.Text == "function":
+		return Function
+	case s.hasPrefix("()") || s.hasPrefix(" ()"):
+		return Function
+	case word == "[[" || word == "]]":
+		return Keyword
+	case word == "in" && shellInClause(s):
+		return Keyword
+	}
+	if shellCommandStart(s) {
+		if kind, ok := shellKeywords.kind(word); ok {
+			return kind
+		}
+	}
+	switch {
+	case word == "{" || word == "}":
+		return Punctuation
+	case strings.Trim(word, "0123456789") == "":
+		return Decimal
+	}
+	return Plaintext
+}
+
+// shellInClause reports whether the current token is in a for, select or
+// case clause on the same line, where "in" is a reserved word.
+func shellInClause(s *shellLexState) bool {
+	for i := len(s.toks) - 1; i >= 0; i-- {
+		switch tok := s.toks[i]; {
+		case tok.Kind == Whitespace && strings.Contains(tok.Text, "\n"), tok.Text == ";":
+			return false
+		case tok.Kind == Keyword:
+			return tok.Text == "for" || tok.Text == "case" || tok.Text == "select"
+		}
+	}
+	return false
+}
+
+// shellCommandStart reports whether the current token starts a command,
+// which is the only place where reserved words and builtins are recognized.
+func shellCommandStart(s *shellLexState) bool {
+	for i := s.start - 1; i >= 0; i-- {
+		switch s.src[i] {
+		case ' ', '\t', '\r':
+			continue
+		case '\n', ';', '|', '&', '(', ')', '`':
+			return true
+		}
+		prev, _ := s.last()
+		switch prev.Kind {
+		case Keyword:
+			return prev.Text != "in" && prev.Text != "]]"
+		case Builtin:
+			return prev.Text == "command" || prev.Text == "builtin" || prev.Text == "exec"
+		}
+		return false
+	}
+	return true
+}
+
+// lexShellExpansion lexes a parameter expansion ($VAR or ${VAR:-x}), a
+// command substitution, an arithmetic expansion or a $'...' string, and
+// reports whether the "$" at pos starts one.
+func lexShellExpansion(s *lexState) bool {
+	switch c := s.peek(1); {
+	case s.hasPrefix("$(("):
+		s.pos += 3
+		s.emit(Punctuation)
+		s.delegateBalanced('(', ')', `"'`, shellLexer{}, Plaintext)
+		s.acceptByte(')')
+		s.acceptByte(')')
+		s.emit(Punctuation)
+	case c == '(':
+		s.pos += 2
+		s.emit(Punctuation)
+		s.delegateBalanced('(', ')', "\"'`", shellLexer{}, Plaintext)
+		s.acceptByte(')')
+		s.emit(Punctuation)
+	case c == '{':
+		s.pos += 2
+		s.acceptBalanced('{', '}', `"'`)
+		s.acceptByte('}')
+		s.emit(Parameter)
+	case isIdentStartByte(c):
+		s.pos++
+		for isIdentStartByte(s.peek(0)) || isDigit(s.peek(0)) {
+			s.pos++
+		}
+		s.emit(Parameter)
+	case isDigit(c) || c != 0 && strings.IndexByte("@*#?$!-", c) >= 0:
+		s.pos += 2
+		s.emit(Parameter)
+	case c == '\'':
+		// ANSI-C quoting, in which backslash escapes work.
+		s.pos += 2
+		s.acceptQuoted("'", true, true)
+		s.emit(String)
+	case c == '"':
+		// A string translated according to the locale.
+		s.pos++
+		lexShellDoubleQuoted(s)
+	default:
+		return false
+	}
+	return true
+}
+
+// lexShellDoubleQuoted lexes a double-quoted string, whose opening quote is
+// at pos.
+func lexShellDoubleQuoted(s *lexState) {
+	s.pos++
+	lexShellContent(s, func() bool { return s.peek(0) == '"' })
+	s.acceptByte('"')
+	s.emit(String)
+}
+
+// lexShellBackquoted lexes an old-style command substitution, `cmd`, whose
+// opening backquote is at pos.
+func lexShellBackquoted(s *lexState) {
+	s.pos++
+	s.emit(Punctuation)
+	for !s.eof() && s.peek(0) != '`' {
+		if s.peek(0) == '\\' {
+			s.pos++
+		}
+		s.pos++
+	}
+	if s.pos > len(s.src) {
+		s.pos = len(s.src)
+	}
+	s.delegate(shellLexer{}, Plaintext)
+	if s.acceptByte('`') {
+		s.emit(Punctuation)
+	}
+}
+
+// lexShellContent lexes the contents of a double-quoted string or a heredoc
+// up to the point where atEnd returns true, highlighting the expansions and
+// command substitutions in them.
+func lexShellContent(s *lexState, atEnd func() bool) {
+	for !s.eof() && !atEnd() {
+		switch s.peek(0) {
+		case '\\':
+			s.pos += 2
+			if s.pos > len(s.src) {
+				s.pos = len(s.src)
+			}
+		case '$':
+			s.emit(String)
+			if !lexShellExpansion(s) {
+				s.pos++
+			}
+		case '`':
+			s.emit(String)
+			lexShellBackquoted(s)
+		default:
+			s.pos++
+		}
+	}
+}
+
+// lexShellHeredocStart lexes the redirection that opens a heredoc, such as
+// <<EOF or <<-'EOF', and reports whether there was one at pos.
+func lexShellHeredocStart(s *shellLexState) bool {
+	i := 2
+	h := shellHeredoc{expand: true}
+	if s.peek(i) == '-' {
+		h.stripTabs = true
+		i++
+	}
+	for s.peek(i) == ' ' || s.peek(i) == '\t' {
+		i++
+	}
+	var id []byte
+	for s.pos+i < len(s.src) && isShellWordPart(s.peek(i)) || s.peek(i) == '\'' || s.peek(i) == '"' {
+		switch q := s.peek(i); q {
+		case '\'', '"':
+			end := bytes.IndexByte(s.src[s.pos+i+1:], q)
+			if end < 0 {
+				return false
+			}
+			id = append(id, s.src[s.pos+i+1:s.pos+i+1+end]...)
+			i += end + 2
+			h.expand = false
+		case '\\':
+			h.expand = false
+			i++
+		default:
+			id = append(id, q)
+			i++
+		}
+	}
+	if len(id) == 0 || bytes.IndexByte(id, '\n') >= 0 {
+		return false
+	}
+	h.id = string(id)
+
+	s.pos += i
+	s.emit(String)
+	s.heredocs = append(s.heredocs, h)
+	return true
+}
+
+// lexShellHeredocBody lexes the body and the terminator of a heredoc, which
+// start at pos.
+func lexShellHeredocBody(s *shellLexState, h shellHeredoc) {
+	bodyEnd := len(s.src)
+	termEnd := len(s.src)
+	for i := s.pos; i < len(s.src); {
+		lineEnd := bytes.IndexByte(s.src[i:], '\n')
+		if lineEnd < 0 {
+			lineEnd = len(s.src)
+		} else {
+			lineEnd += i
+		}
+		line := bytes.TrimRight(s.src[i:lineEnd], "\r")
+		if h.stripTabs {
+			line = bytes.TrimLeft(line, "\t")
+		}
+		if string(line) == h.id {
+			bodyEnd, termEnd = i, lineEnd
+			break
+		}
+		i = lineEnd + 1
+	}
+
+	// The body is lexed on the source cut at its end, so that an expansion
+	// that isn't closed doesn't run past it.
+	src := s.src
+	s.src = s.src[:bodyEnd]
+	if h.expand {
+		lexShellContent(s.lexState, func() bool { return false })
+	} else {
+		s.pos = bodyEnd
+	}
+	s.emit(String)
+	s.src = src
+	s.pos = termEnd
+	s.emit(String)
+}
@@ -0,0 +1,219 @@
+package syntaxhighlight
+
+import (
+	"regexp"
+	"strings"
+)
+
+// sqlDialect is a dialect of SQL. Dialects differ in their keywords and in
+// the syntax of strings, quoted identifiers, comments and bind parameters.
+type sqlDialect int
+
+const (
+	sqlStandard sqlDialect = iota
+	sqlPostgres
+	sqlMySQL
+	sqlSQLite
+)
+
+// sqlLexer tokenizes SQL in the given dialect. Keywords are recognized
+// regardless of their case.
+type sqlLexer struct {
+	dialect sqlDialect
+}
+
+func init() {
+	Register(LexerConfig{
+		Name:      "sql",
+		Filenames: []string{"*.sql"},
+		MimeTypes: []string{"text/x-sql", "application/sql"},
+	}, sqlLexer{sqlStandard})
+	Register(LexerConfig{
+		Name:      "postgresql",
+		Aliases:   []string{"postgres", "pgsql", "plpgsql"},
+		Filenames: []string{"*.pgsql"},
+		MimeTypes: []string{"text/x-postgresql"},
+	}, sqlLexer{sqlPostgres})
+	Register(LexerConfig{
+		Name:      "mysql",
+		Aliases:   []string{"mariadb"},
+		MimeTypes: []string{"text/x-mysql"},
+	}, sqlLexer{sqlMySQL})
+	Register(LexerConfig{
+		Name:      "sqlite",
+		Aliases:   []string{"sqlite3"},
+		MimeTypes: []string{"text/x-sqlite"},
+	}, sqlLexer{sqlSQLite})
+}
+
+var sqlOperators = []string{
+	"->>", "#>>", "::", "<>", "!=", "<=", ">=", "||", "->", "#>", "@>", "<@",
+	":=", "<=>", "<<", ">>", "&&",
+}
+
+// sqlDollarQuote matches the delimiter of a PostgreSQL dollar-quoted string,
+// such as $$ or $body$.
+var sqlDollarQuote = regexp.MustCompile(`^\$([A-Za-z_][A-Za-z0-9_]*)?\$`)
+
+func (l sqlLexer) Tokens(src []byte) ([]Token, error) {
+	s := newLexState(src)
+	for !s.eof() {
+		l.lex(s)
+	}
+	return s.toks, nil
+}
+
+func (l sqlLexer) keywordTable() *keywordTable {
+	switch l.dialect {
+	case sqlPostgres:
+		return postgresKeywords
+	case sqlMySQL:
+		return mysqlKeywords
+	case sqlSQLite:
+		return sqliteKeywords
+	}
+	return sqlKeywords
+}
+
+// lex lexes a single SQL token.
+func (l sqlLexer) lex(s *lexState) {
+	if s.lexWhitespace() {
+		return
+	}
+	c := s.peek(0)
+	switch {
+	case s.hasPrefix("--"), c == '#' && l.dialect == sqlMySQL:
+		s.acceptLine()
+		s.emit(Comment)
+	case s.hasPrefix("/*") && l.dialect == sqlPostgres:
+		s.acceptNested("/*", "*/")
+		s.emit(Comment)
+	case s.hasPrefix("/*"):
+		if s.acceptUntil("*/") {
+			s.pos += 2
+		}
+		s.emit(Comment)
+	case c == '\'':
+		l.lexString(s, l.dialect == sqlMySQL)
+	case s.peek(1) == '\'' && strings.IndexByte("eE", c) >= 0 && l.dialect == sqlPostgres:
+		// An escape string, E'...', in which backslash escapes work.
+		s.pos++
+		l.lexString(s, true)
+	case s.peek(1) == '\'' && strings.IndexByte("bBnNxX", c) >= 0:
+		// A bit string, national character string or hex string.
+		s.pos++
+		l.lexString(s, false)
+	case c == '"' && l.dialect == sqlMySQL:
+		l.lexString(s, true)
+	case c == '"', c == '`' && l.dialect != sqlPostgres:
+		// A quoted identifier.
+		s.pos++
+		for !s.eof() {
+			if s.acceptByte(c) {
+				if !s.acceptByte(c) {
+					break
+				}
+				continue
+			}
+			s.pos++
+		}
+		s.emit(Plaintext)
+	case c == '[' && l.dialect == sqlSQLite:
+		s.pos++
+		if !s.acceptUntil("]") {
+			s.pos = s.start + 1
+			s.emit(Punctuation)
+			return
+		}
+		s.pos++
+		s.emit(Plaintext)
+	case c == '$' && l.dialect == sqlPostgres && sqlDollarQuote.Match(s.src[s.pos:]):
+		delim := sqlDollarQuote.Find(s.src[s.pos:])
+		s.pos += len(delim)
+		if s.acceptUntil(string(delim)) {
+			s.pos += len(delim)
+		}
+		s.emit(String)
+	case l.acceptParameter(s):
+		s.emit(Parameter)
+	case s.acceptNumber(0):
+		s.emit(Decimal)
+	case isIdentStartByte(c) || c >= 0x80:
+		r, w := s.peekRune()
+		if !isIdentStart(r) {
+			s.pos += w
+			s.emit(Punctuation)
+			return
+		}
+		s.acceptWhile(func(r rune) bool { return isIdentPart(r) || r == '$' })
+		s.emit(l.identKind(s))
+	case s.acceptAny(sqlOperators):
+		s.emit(Punctuation)
+	default:
+		_, w := s.peekRune()
+		s.pos += w
+		s.emit(Punctuation)
+	}
+}
+
+// lexString lexes a string literal, whose quote is at pos. Quotes are
+// escaped by doubling them, and, if escapes is set, with a backslash.
+func (l sqlLexer) lexString(s *lexState, escapes bool) {
+	quote := s.peek(0)
+	s.pos++
+	for !s.eof() {
+		switch c := s.peek(0); {
+		case c == quote && s.peek(1) == quote:
+			s.pos += 2
+		case c == quote:
+			s.pos++
+			s.emit(String)
+			return
+		case c == '\\' && escapes && s.pos+1 < len(s.src):
+			s.pos += 2
+		default:
+			s.pos++
+		}
+	}
+	s.emit(String)
+}
+
+// acceptParameter advances past a bind parameter, such as ?, $1, :name or
+// @name, or a MySQL user variable, and reports whether there was one at pos.
+func (l sqlLexer) acceptParameter(s *lexState) bool {
+	c, next := s.peek(0), s.peek(1)
+	switch {
+	case c == '?' && l.dialect != sqlPostgres:
+		s.pos++
+		for isDigit(s.peek(0)) {
+			s.pos++
+		}
+		return true
+	case c == '$' && isDigit(next) && (l.dialect == sqlPostgres || l.dialect == sqlSQLite):
+		s.pos++
+		for isDigit(s.peek(0)) {
+			s.pos++
+		}
+		return true
+	case c == '$' && isIdentStartByte(next) && l.dialect == sqlSQLite,
+		c == ':' && isIdentStartByte(next),
+		c == '@' && isIdentStartByte(next) && l.dialect != sqlPostgres,
+		c == '@' && next == '@' && l.dialect == sqlMySQL:
+		s.pos++
+		s.acceptByte('@')
+		s.acceptWhile(func(r rune) bool { return isIdentPart(r) || r == '.' && l.dialect == sqlMySQL })
+		return true
+	}
+	return false
+}
+
+// identKind returns the kind of the identifier that is the current token.
+func (l sqlLexer) identKind(s *lexState) Kind {
+	if prev, _ := s.last(); prev.Text == "." {
+		return Plaintext
+	}
+	if kind, ok := l.keywordTable().kind(s.text()); ok {
+		return kind
+	}
+	return Plaintext
+}
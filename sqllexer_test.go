@@ -0,0 +1,55 @@
+package syntaxhighlight
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSQLLexer(t *testing.T) {
+	tests := []struct {
+		lang string
+		src  string
+		want []Token
+	}{
+		{"sql", "Select x FROM t", []Token{
+			{Keyword, 0, "Select"}, {Whitespace, 6, " "}, {Plaintext, 7, "x"},
+			{Whitespace, 8, " "}, {Keyword, 9, "FROM"}, {Whitespace, 13, " "},
+			{Plaintext, 14, "t"},
+		}},
+		{"sql", "'it''s' -- c", []Token{
+			{String, 0, "'it''s'"}, {Whitespace, 7, " "}, {Comment, 8, "-- c"},
+		}},
+		{"sql", `"select"=?`, []Token{
+			{Plaintext, 0, `"select"`}, {Punctuation, 8, "="}, {Parameter, 9, "?"},
+		}},
+		{"postgresql", "$f$ a $$ b $f$::jsonb", []Token{
+			{String, 0, "$f$ a $$ b $f$"}, {Punctuation, 14, "::"}, {Type, 16, "jsonb"},
+		}},
+		{"postgresql", `E'\'' $1`, []Token{
+			{String, 0, `E'\''`}, {Whitespace, 5, " "}, {Parameter, 6, "$1"},
+		}},
+		{"mysql", "`order` # c", []Token{
+			{Plaintext, 0, "`order`"}, {Whitespace, 7, " "}, {Comment, 8, "# c"},
+		}},
+		{"mysql", `"a\"b" @@x`, []Token{
+			{String, 0, `"a\"b"`}, {Whitespace, 6, " "}, {Parameter, 7, "@@x"},
+		}},
+		{"sql", "1ı 0x1ņ", []Token{
+			{Decimal, 0, "1"}, {Plaintext, 1, "ı"}, {Whitespace, 3, " "},
+			{Decimal, 4, "0x1"}, {Plaintext, 7, "ņ"},
+		}},
+		{"sqlite", "[a b] :c", []Token{
+			{Plaintext, 0, "[a b]"}, {Whitespace, 5, " "}, {Parameter, 6, ":c"},
+		}},
+	}
+	for _, test := range tests {
+		got, err := Lookup(test.lang).Tokens([]byte(test.src))
+		if err != nil {
+			t.Fatal(err)
+		}
+		checkTokens(t, test.src, []byte(test.src), got)
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("%s: %q:\ngot  %v\nwant %v", test.lang, test.src, got, test.want)
+		}
+	}
+}
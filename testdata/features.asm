@@ -0,0 +1,22 @@
+; Hello world in NASM.
+%define SYS_WRITE 1
+%macro exit 1
+	mov rax, 60
+	mov rdi, %1
+	syscall
+%endmacro
+
+section .data
+msg db "Hello, world", 10
+len equ $ - msg
+
+section .text
+global _start
+_start:
+	mov rax, SYS_WRITE
+	mov rdi, 1
+	lea rsi, [rel msg]
+	mov rdx, len
+	mov byte [rsp-8], 0FFh
+	syscall
+.done:	exit 0
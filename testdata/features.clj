@@ -0,0 +1,38 @@
+#!/usr/bin/env bb
+;; A small Clojure namespace.
+(ns example.core
+  (:require [clojure.string :as str])
+  (:import (java.util Date)))
+
+(def ^:private default-port 8080)
+
+(defn- valid-name? [s]
+  (and (string? s) (not (str/blank? s))))
+
+(defn greet
+  "Greets a user by name."
+  [{:keys [name] :or {name "world"}} & opts]
+  (let [n (count opts)
+        ratio 22/7
+        big 42N
+        hex 0xFF]
+    (when (valid-name? name)
+      (println (str "Hello, " name \! \newline)))
+    (swap! counter inc)
+    @counter))
+
+(defrecord Point [x y])
+
+(defmacro unless [test & body]
+  `(if (not ~test) (do ~@body) nil))
+
+(def letters #{\a \b é})
+(def pattern #"\d+-[a-z]*")
+(def squares (map #(* % %) (range 10)))
+(def data '(foo bar :baz 1.5M))
+(def hint (fn [^String s] (.toUpperCase s)))
+(def when-ready #inst "2024-01-01T00:00:00Z")
+(def ns-key ::local)
+#_(println "ignored" (nested form))
+(def infinity ##Inf)
+(def v #'greet)
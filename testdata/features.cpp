@@ -0,0 +1,39 @@
+#include <vector>
+#include "config.h"
+#  pragma once
+#define MAX(a, b) \
+	((a) > (b) ? (a) : (b))
+#define STR(x) #x
+#define CAT(a, b) a##b
+#if defined(DEBUG) && __has_include(<optional>)
+#error "don't do this"
+#endif /* DEBUG */
+
+namespace app {
+
+using namespace std::literals;
+
+template <typename T>
+class Buffer : public Base {
+public:
+	explicit Buffer(std::size_t n) : data_(n) {}
+	virtual ~Buffer() noexcept = default;
+
+private:
+	std::vector<T> data_;
+};
+
+constexpr auto kMillion = 1'000'000;
+constexpr double kRatio = 0x1.8p3 + 1e-9f;
+const auto *sql = R"sql(SELECT "x" FROM t WHERE a = ')')sql";
+auto s = u8"utf-8" + L"wide"s + 'c' + u'\n';
+auto d = 12_km + 3.5ms;
+
+int main(int argc, char **argv) {
+	if (argc > 1 && argv[1] != nullptr) {
+		return MAX(argc, 2) <=> 0;
+	}
+	return 0;
+}
+
+} // namespace app
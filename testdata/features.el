@@ -0,0 +1,35 @@
+;;; features.el --- A small Emacs Lisp package  -*- lexical-binding: t -*-
+
+;;; Code:
+
+(require 'cl-lib)
+
+(defgroup features nil
+  "Features of Emacs Lisp."
+  :group 'tools)
+
+(defcustom features-width 80
+  "The width of the features buffer."
+  :type 'integer)
+
+(defvar features--count 0)
+
+(defun features-insert (text &optional times)
+  "Insert TEXT TIMES times."
+  (interactive "sText: ")
+  (dotimes (_ (or times 1))
+    (insert text ?\n))
+  (setq features--count (1+ features--count)))
+
+(defmacro features-with-buffer (&rest body)
+  `(with-current-buffer (get-buffer-create "*features*")
+     ,@body))
+
+(global-set-key (kbd "C-c f") #'features-insert)
+(define-key global-map [?\C-\M-x] 'features-insert)
+(mapcar (lambda (c) (eq c ?a)) '(?a ?b ?\s ?\^I))
+(list #x1F #b101 -3.5e2 nil t [1 2 3] #s(hash-table data (a 1)))
+(when (null foo\ bar) (message "%d" 42))
+
+(provide 'features)
+;;; features.el ends here
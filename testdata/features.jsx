@@ -0,0 +1,27 @@
+#!/usr/bin/env node
+/* A block comment */
+import React, { useState } from 'react';
+
+const RE = /ab+c\/[/]/gi;
+let ratio = total / count / 2;
+const big = 10_000n + 0xFFn;
+
+export default function Counter({ start = 0, label }) {
+  const [count, setCount] = useState(start);
+  const message = `${label}: ${count > 1 ? `${count} clicks` : 'one click'}`;
+  if (count < limit && !done) {
+    console.log(message?.length ?? 0);
+  }
+  return (
+    <div className="counter" data-count={count}>
+      <Button onClick={() => setCount(count + 1)} disabled />
+      <p>It's been {count} clicks</p>
+      <>fragment</>
+    </div>
+  );
+}
+
+class Store extends Base {
+  #items = [];
+  get size() { return this.#items.length; }
+}
@@ -0,0 +1,43 @@
+# Build and install the tool.
+
+PREFIX ?= /usr/local
+GO := go
+VERSION != git describe --tags 2>/dev/null || echo dev
+SRCS = $(wildcard *.go) \
+	$(wildcard cmd/*/*.go)
+OBJS := $(patsubst %.c,%.o,$(SRCS))
+LDFLAGS += -X main.version=$(VERSION)
+export GOFLAGS = -mod=mod
+
+ifeq ($(shell uname -s),Darwin)
+	SED := gsed
+else
+	SED := sed
+endif
+
+define HELP
+Usage: make [target]
+  all      build $(NAME)
+endef
+
+.PHONY: all install clean
+
+all: bin/tool
+
+bin/tool: $(SRCS) | bin
+	@echo "building $@ from $< ($(words $^) files)"
+	$(GO) build -ldflags "$(LDFLAGS)" -o $@ ./cmd/tool
+
+%.o: %.c ; $(CC) -c $< -o $@
+
+install: CFLAGS += -O2
+install: all
+	-mkdir -p $(DESTDIR)$(PREFIX)/bin
+	for f in bin/*; do \
+		install -m 755 "$$f" '$(DESTDIR)$(PREFIX)/bin'; \
+	done
+
+clean:
+	rm -rf bin # remove the binaries
+
+include $(wildcard *.d)
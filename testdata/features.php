@@ -0,0 +1,45 @@
+<?php
+declare(strict_types=1);
+
+namespace App\Controller;
+
+use App\Models\User;
+
+# A shell-style comment.
+#[Route("/users/{id}", methods: ["GET"])]
+final class UserController extends BaseController implements \JsonSerializable
+{
+    public const MAX_USERS = 1_000;
+    private static ?User $current = null;
+
+    /**
+     * Shows a user.
+     */
+    public function show(int $id, string ...$fields): ?array
+    {
+        $user = User::find($id) ?? throw new NotFoundException("no user $id");
+        $name = "{$user->name} <$user->email> #{$fields[0]}";
+        $this->log('Shown \'user\'', $user?->id);
+        echo <<<HTML
+            <p>Hello, $name! You have {$user->count()} messages.</p>
+            HTML;
+        $raw = <<<'SQL'
+            SELECT * FROM users WHERE id = $id
+            SQL;
+        return ['id' => $id, 'items' => array_map(fn($x) => $x * 2.5, $user->items), 0x1F];
+    }
+}
+?>
+<!DOCTYPE html>
+<html>
+<body class="<?= $theme ?>">
+  <?php if (count($users) > 0): // a comment ends at ?>
+    <ul>
+    <?php foreach ($users as $u): ?>
+      <li><a href="/users/<?= $u->id ?>"><?= htmlspecialchars($u->name) ?></a></li>
+    <?php endforeach; ?>
+    </ul>
+  <?php endif ?>
+  <script>const n = <?= json_encode($n) ?>;</script>
+</body>
+</html>
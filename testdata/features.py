@@ -0,0 +1,39 @@
+#!/usr/bin/env python3
+# -*- coding: utf-8 -*-
+"""Module docstring with 'quotes' and "double quotes".
+
+It spans several lines.
+"""
+import re
+from dataclasses import dataclass
+
+MAX_SIZE = 1_000
+PATTERN = re.compile(r"\d+\.\d*")
+DATA = b'\x00\xff' + rb'\raw'
+
+
+@dataclass(frozen=True)
+class Point:
+    x: float = 0.0
+    y: float = 1e-3
+
+    def __repr__(self) -> str:
+        return f"Point({self.x!r}, {self.y:.2f}) {{literal}} {'nested'}"
+
+
+async def fetch(url, *, timeout=10):
+    await sleep(0.5j)
+    if (n := len(url)) >= MAX_SIZE:
+        raise ValueError(f'too long: {n}')
+    return None
+
+
+def check(command):
+    match command:
+        case [action, obj]:
+            return action
+        case _:
+            pass
+    match = command  # a variable, not a statement
+    type Alias = list[int]
+    return match
@@ -0,0 +1,45 @@
+# frozen_string_literal: true
+require 'json'
+
+=begin
+A block comment.
+=end
+
+module Shop
+  VERSION = "1.2.#{BUILD}"
+
+  class Cart < Base
+    attr_reader :items, :total
+
+    def initialize(items = [])
+      @items = items
+      @@count ||= 0
+      $stdout.puts "new cart with #{items.size} items"
+    end
+
+    def self.empty?
+      @items.empty? && !@closed
+    end
+
+    def add(item, qty: 1, **opts)
+      raise ArgumentError, 'bad quantity' unless qty > 0
+      @items << { item: item, qty: qty, "key" => :value }
+      words = %w[apple banana cherry]
+      syms = %i(a b c)
+      quoted = %q{it's {nested} here}
+      matches = item.name =~ /^[a-z]+\d*$/i
+      ratio = qty / 2 % 3
+      text = <<~EOS.strip
+        Item: #{item.name}
+          indented #{qty * 2}
+      EOS
+      raw = <<-'RAW'
+        no #{interpolation} here
+        RAW
+      `echo #{words.join(',')}`
+    end
+  end
+end
+
+__END__
+data that is not code
@@ -0,0 +1,44 @@
+#![allow(dead_code)]
+//! Crate docs.
+
+use std::collections::HashMap;
+
+/// A parser with a lifetime.
+#[derive(Debug, Clone)]
+#[doc = "has ] in it"]
+pub struct Parser<'a, T: 'static> {
+    input: &'a str,
+    items: Vec<T>,
+}
+
+/* outer /* nested */ still a comment */
+
+impl<'a, T> Parser<'a, T> {
+    pub fn new(input: &'a str) -> Self {
+        let c = 'x';
+        let nl = '\n';
+        let b = b'y';
+        let bytes = b"bytes\n";
+        let raw = r#"a "quoted" string"#;
+        let raw2 = r"C:\path";
+        let n = 1_000u32 + 0xff_u8 as u32 + 1e-3f64 as u32;
+        let t = (1, 2).0;
+        let r#type = 0..=10;
+        println!("{} {}", c, nl);
+        'outer: loop {
+            break 'outer;
+        }
+        Parser { input, items: Vec::new() }
+    }
+}
+
+macro_rules! square {
+    ($x:expr) => { $x * $x };
+}
+
+enum Shape { Circle(f64), Square(f64) }
+
+fn main() {
+    let m: HashMap<String, Option<i32>> = HashMap::new();
+    if m.is_empty() != false { return; }
+}
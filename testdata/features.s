@@ -0,0 +1,23 @@
+// Copyright 2024 The Go Authors.
+
+#include "textflag.h"
+
+// func add(x, y int64) int64
+TEXT ·add(SB), NOSPLIT, $0-24
+	MOVQ x+0(FP), AX
+	MOVQ y+8(FP), BX
+	ADDQ BX, AX
+	MOVQ AX, ret+16(FP)
+	RET
+
+TEXT runtime·memclr<>(SB), NOSPLIT|NOFRAME, $16
+	XORL	CX, CX /* zero */
+loop:
+	MOVB	$0x10, (DI)(CX*1)
+	INCQ	CX; CMPQ CX, $-1
+	JNE	loop
+	CALL	·helper(SB)
+	RET
+
+DATA ·table+0(SB)/8, $"abc\x00\x00\x00\x00\x00"
+GLOBL ·table(SB), RODATA, $8
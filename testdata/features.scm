@@ -0,0 +1,34 @@
+#!r6rs
+;;; A small Scheme library.
+#| Block comments
+   #| nest |# in Scheme. |#
+(define (square x)
+  (* x x))
+
+(define-record-type point
+  (make-point x y)
+  point?
+  (x point-x)
+  (y point-y set-point-y!))
+
+(define (classify n)
+  (cond ((negative? n) 'negative)
+        ((zero? n) 'zero)
+        (else 'positive)))
+
+(define chars (list #\a #\space #\x41 #\())
+(define flags (vector #t #f #true))
+(define nums '(42 -1.5e3 1/3 #xFF #b1010 #e1.5))
+(define bytes #u8(1 2 3))
+(define-syntax swap!
+  (syntax-rules ()
+    ((_ a b) (let ((tmp a)) (set! a b) (set! b tmp)))))
+
+(define template `(1 ,(+ 1 1) ,@(list 3 4)))
+#;(display "commented out")
+(let loop ((i 0))
+  (when (< i 3)
+    (display (string-append "i = " (number->string i)))
+    (newline)
+    (loop (+ i 1))))
+(call/cc (lambda (k) (k #:key)))
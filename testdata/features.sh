@@ -0,0 +1,40 @@
+#!/usr/bin/env bash
+# Install script.
+set -euo pipefail
+
+PREFIX="${PREFIX:-/usr/local}"
+args+=(--verbose)
+readonly VERSION=1.2.3
+
+log() {
+	echo "[$(date +%T)] $*" >&2
+}
+
+function cleanup {
+	rm -rf "$tmp" # not a comment: a#b
+}
+trap cleanup EXIT
+
+if [[ -z $HOME && $# -gt 0 ]]; then
+	echo 'single $quotes \' done
+fi
+
+for f in *.go; do
+	case "$f" in
+	*_test.go) continue ;;
+	*) echo "file: ${f%.go} in $(basename "$(pwd)")" ;;
+	esac
+done
+
+count=$((1 + 2 * $#))
+echo `uname -s` $'tab\there' 2>&1 | tee -a log.txt
+
+cat <<EOF > config
+home=$HOME
+sum=$((count + 1))
+EOF
+
+cat <<-'RAW'
+	literal $HOME and $(not run)
+	RAW
+echo done
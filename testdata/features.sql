@@ -0,0 +1,15 @@
+-- Schema for the users table.
+CREATE TABLE IF NOT EXISTS users (
+    id INTEGER PRIMARY KEY,
+    "name" VARCHAR(255) NOT NULL,
+    created_at timestamp DEFAULT CURRENT_TIMESTAMP
+);
+
+/* Find active users. */
+select u.id, count(*) as n, 'O''Brien' AS "quoted"
+from users u
+left join orders o on o.user_id = u.id
+where u.name like :pattern and u.id > ? and score >= 1.5e3
+group by u.id
+having Count(*) > 0
+order by n desc nulls last;
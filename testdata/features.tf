@@ -0,0 +1,59 @@
+# Terraform configuration for the web tier.
+terraform {
+  required_version = ">= 1.5"
+  required_providers {
+    aws = {
+      source  = "hashicorp/aws"
+      version = "~> 5.0"
+    }
+  }
+}
+
+variable "region" {
+  type    = string
+  default = "us-east-1" // the default region
+}
+
+variable "users" {
+  type = map(object({ name = string, admin = optional(bool) }))
+}
+
+/*
+ * Instances.
+ */
+resource "aws_instance" "web" {
+  count         = var.instances > 0 ? var.instances : 1
+  ami           = data.aws_ami.ubuntu.id
+  instance_type = lookup(var.types, var.region, "t3.micro")
+  tags = {
+    Name          = "web-${count.index + 1}"
+    "app.example" = local.app
+    Escaped       = "$${not.interpolated} and \"quotes\""
+  }
+
+  user_data = <<-EOT
+    #!/bin/bash
+    echo "region ${var.region}"
+    %{ for ip in var.allowed ~}
+    allow ${ip};
+    %{ endfor ~}
+  EOT
+
+  lifecycle {
+    ignore_changes = [tags["Owner"], user_data]
+  }
+}
+
+locals {
+  app     = "demo"
+  enabled = true
+  ports   = [for p in [80, 443] : p if p != 0]
+  names   = { for k, v in var.users : k => upper(v.name) }
+  arn     = provider::aws::arn_parse(aws_instance.web[0].arn)
+  empty   = null
+  ratio   = 1.5e3
+}
+
+output "ids" {
+  value = aws_instance.web[*].id
+}
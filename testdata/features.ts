@@ -0,0 +1,25 @@
+import type { Request } from "express";
+
+@Injectable()
+export class UserService implements Service {
+  private readonly cache: Map<string, User> = new Map();
+
+  constructor(private http: HttpClient) {}
+
+  async find(id: number, opts?: Options): Promise<User | undefined> {
+    const key = <string>id.toString();
+    return this.cache.get(key) as User;
+  }
+}
+
+type Handler<T extends object> = (req: Request, body: T) => void;
+
+interface User {
+  readonly id: number;
+  name: string;
+  tags?: string[];
+}
+
+enum Color { Red = 1, Green, Blue }
+declare namespace NodeJS {}
+let x: unknown = a < b ? c : d;
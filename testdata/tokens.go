@@ -0,0 +1,31 @@
+package tokens
+
+import "fmt"
+
+type Point[T ~int | ~float64] struct {
+	X, Y T `json:"x,omitempty" xml:"x"`
+	Name string `not a conventional tag`
+	Raw  []byte "interpreted"
+}
+
+const (
+	a = iota
+	b
+)
+
+func run(ch chan<- rune) (err error) {
+	x := 1_000 + 0x1F
+	var z complex128 = 2.5i
+	r := 'a'
+	esc := '\n'
+	ok := x >= 2 && b != 0 || !false
+	x <<= 2
+	x &^= 1
+	ch <- r
+	s := []string{"a", `raw`}
+	s = append(s, fmt.Sprint(len(s), cap(s), nil, true, esc, z, ok))
+	/* block
+	comment */
+	defer recover()
+	return nil
+}
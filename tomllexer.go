@@ -0,0 +1,171 @@
+package syntaxhighlight
+
+import "regexp"
+
+// tomlLexer tokenizes TOML documents. Keys are highlighted as Field and the
+// names of tables as Type. Values are highlighted by their type: strings as
+// String, numbers as Decimal, and booleans and datetimes as Literal.
+type tomlLexer struct{}
+
+func init() {
+	Register(LexerConfig{
+		Name:      "toml",
+		Filenames: []string{"*.toml", "Cargo.lock", "Pipfile", "poetry.lock"},
+		MimeTypes: []string{"application/toml"},
+	}, tomlLexer{})
+}
+
+// tomlLexState is the state of the TOML lexer.
+type tomlLexState struct {
+	*lexState
+	brackets []byte // the open brackets of the arrays and inline tables around pos
+}
+
+func (tomlLexer) Tokens(src []byte) ([]Token, error) {
+	s := &tomlLexState{lexState: newLexState(src)}
+	for !s.eof() {
+		lexTOML(s)
+	}
+	return s.toks, nil
+}
+
+var (
+	// tomlDatetime matches an offset or local datetime, date or time.
+	tomlDatetime = regexp.MustCompile(`^(\d{4}-\d{2}-\d{2}([Tt ]\d{2}:\d{2}(:\d{2}(\.\d+)?)?([Zz]|[-+]\d{2}:\d{2})?)?|\d{2}:\d{2}:\d{2}(\.\d+)?)`)
+
+	// tomlNumber matches an integer or a float.
+	tomlNumber = regexp.MustCompile(`^([-+]?(inf|nan)|0x[\da-fA-F_]+|0o[0-7_]+|0b[01_]+|[-+]?\d[\d_]*(\.\d[\d_]*)?([eE][-+]?\d[\d_]*)?)`)
+)
+
+// lexTOML lexes a single TOML token, or a whole table header.
+func lexTOML(s *tomlLexState) {
+	if s.lexWhitespace() {
+		return
+	}
+	c := s.peek(0)
+	switch {
+	case c == '#':
+		s.acceptLine()
+		s.emit(Comment)
+	case c == '[' && len(s.brackets) == 0 && s.expectKey():
+		lexTOMLTableHeader(s)
+	case c == '[' || c == '{':
+		s.brackets = append(s.brackets, c)
+		s.pos++
+		s.emit(Punctuation)
+	case c == ']' || c == '}':
+		if len(s.brackets) > 0 {
+			s.brackets = s.brackets[:len(s.brackets)-1]
+		}
+		s.pos++
+		s.emit(Punctuation)
+	case s.expectKey():
+		if !acceptTOMLKey(s.lexState) {
+			_, w := s.peekRune()
+			s.pos += w
+			s.emit(Punctuation)
+			return
+		}
+		s.emit(Field)
+	case s.hasPrefix(`"""`) || s.hasPrefix("'''"):
+		quote := string(s.src[s.pos : s.pos+3])
+		s.pos += 3
+		for !s.eof() && !s.hasPrefix(quote) {
+			if s.peek(0) == '\\' && quote == `"""` {
+				s.pos++
+			}
+			s.pos++
+		}
+		s.pos += len(quote)
+		// Up to two quotes may directly precede the closing delimiter.
+		for i := 0; i < 2 && s.peek(0) == quote[0]; i++ {
+			s.pos++
+		}
+		if s.pos > len(s.src) {
+			s.pos = len(s.src)
+		}
+		s.emit(String)
+	case c == '"' || c == '\'':
+		s.pos++
+		s.acceptQuoted(string(c), c == '"', false)
+		s.emit(String)
+	case tomlDatetime.Match(s.src[s.pos:]):
+		s.pos += len(tomlDatetime.Find(s.src[s.pos:]))
+		s.emit(Literal)
+	case tomlNumber.Match(s.src[s.pos:]):
+		s.pos += len(tomlNumber.Find(s.src[s.pos:]))
+		s.emit(Decimal)
+	case s.hasPrefix("true") || s.hasPrefix("false"):
+		s.acceptWhile(isTOMLBareKeyPart)
+		s.emit(Literal)
+	default:
+		_, w := s.peekRune()
+		s.pos += w
+		s.emit(Punctuation)
+	}
+}
+
+// expectKey reports whether a key may start at pos: at the start of a line,
+// after the dot in a dotted key, or after the opening brace or a comma of an
+// inline table.
+func (s *tomlLexState) expectKey() bool {
+	prev := s.lastN(2)
+	if len(prev) > 0 && prev[len(prev)-1].Text == "." {
+		return len(prev) == 2 && prev[0].Kind == Field
+	}
+	if len(s.brackets) > 0 {
+		top := s.brackets[len(s.brackets)-1]
+		return top == '{' && len(prev) > 0 && (prev[len(prev)-1].Text == "{" || prev[len(prev)-1].Text == ",")
+	}
+	if len(prev) == 0 {
+		return true
+	}
+	last := prev[len(prev)-1]
+	for i := last.Offset + len(last.Text); i < s.start; i++ {
+		if s.src[i] == '\n' {
+			return true
+		}
+	}
+	return false
+}
+
+// lexTOMLTableHeader lexes a table header, such as [server] or
+// [[products]], whose name is highlighted as Type.
+func lexTOMLTableHeader(s *tomlLexState) {
+	s.pos++
+	s.acceptByte('[')
+	s.emit(Punctuation)
+	for !s.eof() && s.peek(0) != ']' && s.peek(0) != '\n' {
+		switch {
+		case s.lexWhitespace():
+		case s.peek(0) == '.':
+			s.pos++
+			s.emit(Punctuation)
+		case acceptTOMLKey(s.lexState):
+			s.emit(Type)
+		default:
+			_, w := s.peekRune()
+			s.pos += w
+			s.emit(Punctuation)
+		}
+	}
+	if s.acceptByte(']') {
+		s.acceptByte(']')
+		s.emit(Punctuation)
+	}
+}
+
+// acceptTOMLKey advances past a bare or quoted key, or a part of a dotted
+// key, and reports whether there was one at pos.
+func acceptTOMLKey(s *lexState) bool {
+	if c := s.peek(0); c == '"' || c == '\'' {
+		s.pos++
+		s.acceptQuoted(string(c), c == '"', false)
+		return true
+	}
+	return s.acceptWhile(isTOMLBareKeyPart)
+}
+
+func isTOMLBareKeyPart(r rune) bool {
+	return 'a' <= r && r <= 'z' || 'A' <= r && r <= 'Z' || '0' <= r && r <= '9' || r == '_' || r == '-'
+}
@@ -0,0 +1,197 @@
+package syntaxhighlight
+
+import (
+	"bytes"
+	"regexp"
+)
+
+// yamlLexer tokenizes YAML documents. Mapping keys are highlighted as Field,
+// and scalar values by their type: strings as String, numbers as Decimal and
+// booleans and nulls as Literal. Anchors and aliases are highlighted as
+// Constant and tags as Type.
+type yamlLexer struct{}
+
+func init() {
+	Register(LexerConfig{
+		Name:      "yaml",
+		Aliases:   []string{"yml"},
+		Filenames: []string{"*.yaml", "*.yml", ".clang-format", ".clang-tidy", ".gemrc"},
+		MimeTypes: []string{"application/yaml", "application/x-yaml", "text/yaml", "text/x-yaml"},
+	}, yamlLexer{})
+}
+
+// yamlLexState is the state of the YAML lexer.
+type yamlLexState struct {
+	*lexState
+	flow int // nesting depth of flow collections, such as [a, b]
+}
+
+func (yamlLexer) Tokens(src []byte) ([]Token, error) {
+	s := &yamlLexState{lexState: newLexState(src)}
+	for !s.eof() {
+		lexYAML(s)
+	}
+	return s.toks, nil
+}
+
+func (yamlLexer) keywordTable() *keywordTable { return yamlKeywords }
+
+// yamlNumber matches the scalars that are numbers.
+var yamlNumber = regexp.MustCompile(`^[-+]?(\d[\d_]*(\.\d*)?([eE][-+]?\d+)?|\.\d+([eE][-+]?\d+)?|0x[\da-fA-F_]+|0o[0-7_]+|\.(inf|Inf|INF)|\.(nan|NaN|NAN))$`)
+
+// lexYAML lexes a single YAML token, or a whole block scalar.
+func lexYAML(s *yamlLexState) {
+	if s.lexWhitespace() {
+		return
+	}
+	c := s.peek(0)
+	column0 := s.start == 0 || s.src[s.start-1] == '\n'
+	switch {
+	case c == '#' && (s.start == 0 || isYAMLSpace(s.src[s.start-1])):
+		s.acceptLine()
+		s.emit(Comment)
+	case column0 && (s.hasPrefix("---") || s.hasPrefix("...")) && isYAMLSpace(s.peek(3)):
+		s.pos += 3
+		s.emit(Keyword)
+	case column0 && c == '%':
+		s.acceptLine()
+		s.emit(Preprocessor)
+	case (c == '-' || c == '?' || c == ':') && isYAMLSpace(s.peek(1)):
+		s.pos++
+		s.emit(Punctuation)
+	case c == '[' || c == '{':
+		s.flow++
+		s.pos++
+		s.emit(Punctuation)
+	case c == ']' || c == '}':
+		if s.flow > 0 {
+			s.flow--
+		}
+		s.pos++
+		s.emit(Punctuation)
+	case c == ',' && s.flow > 0, c == ':' && s.flow > 0:
+		s.pos++
+		s.emit(Punctuation)
+	case c == '&' || c == '*':
+		s.pos++
+		s.acceptWhile(func(r rune) bool { return r > ' ' && r != ',' && r != '[' && r != ']' && r != '{' && r != '}' })
+		s.emit(Constant)
+	case c == '!':
+		s.pos++
+		s.acceptWhile(func(r rune) bool { return r > ' ' && r != ',' && r != '[' && r != ']' && r != '{' && r != '}' })
+		s.emit(Type)
+	case (c == '|' || c == '>') && s.flow == 0:
+		lexYAMLBlockScalar(s)
+	case c == '"' || c == '\'':
+		s.pos++
+		s.acceptQuoted(string(c), c == '"', true)
+		if yamlIsKey(s) {
+			s.emit(Field)
+		} else {
+			s.emit(String)
+		}
+	default:
+		lexYAMLPlainScalar(s)
+	}
+}
+
+func isYAMLSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\r' || c == '\n' || c == 0
+}
+
+// yamlIsKey reports whether the current token is followed by the ":" that
+// makes it a mapping key.
+func yamlIsKey(s *yamlLexState) bool {
+	i := s.pos
+	for i < len(s.src) && (s.src[i] == ' ' || s.src[i] == '\t') {
+		i++
+	}
+	return i < len(s.src) && s.src[i] == ':' && (s.flow > 0 || i+1 == len(s.src) || isYAMLSpace(s.src[i+1]))
+}
+
+// lexYAMLPlainScalar lexes an unquoted scalar, which ends at the end of the
+// line, before a comment or before the ":" that makes it a key.
+func lexYAMLPlainScalar(s *yamlLexState) {
+	for !s.eof() {
+		c := s.peek(0)
+		if c == '\n' || c == '\r' ||
+			c == ':' && (isYAMLSpace(s.peek(1)) || s.flow > 0) ||
+			(c == ' ' || c == '\t') && s.peek(1) == '#' ||
+			s.flow > 0 && (c == ',' || c == ']' || c == '}') {
+			break
+		}
+		s.pos++
+	}
+	for s.pos > s.start+1 && (s.src[s.pos-1] == ' ' || s.src[s.pos-1] == '\t') {
+		s.pos--
+	}
+	if s.pos == s.start {
+		s.pos++
+		s.emit(Punctuation)
+		return
+	}
+
+	text := s.text()
+	_, literal := yamlKeywords.kind(text)
+	switch {
+	case yamlIsKey(s):
+		s.emit(Field)
+	case literal:
+		s.emit(Literal)
+	case yamlNumber.MatchString(text):
+		s.emit(Decimal)
+	default:
+		s.emit(String)
+	}
+}
+
+// lexYAMLBlockScalar lexes a literal (|) or folded (>) block scalar: its
+// header and the more indented lines that follow it.
+func lexYAMLBlockScalar(s *yamlLexState) {
+	lineStart := bytes.LastIndexByte(s.src[:s.start], '\n') + 1
+	indent := s.start - lineStart
+	if prev := s.lastN(2); len(prev) == 2 && prev[1].Text == ":" && prev[0].Offset >= lineStart {
+		indent = prev[0].Offset - lineStart // the key's column
+	} else if len(prev) > 0 && prev[len(prev)-1].Text == "-" {
+		indent = prev[len(prev)-1].Offset - lineStart
+	}
+
+	s.pos++
+	s.acceptWhile(func(r rune) bool { return r == '+' || r == '-' || '1' <= r && r <= '9' })
+	s.emit(Punctuation)
+	s.acceptWhile(func(r rune) bool { return r == ' ' || r == '\t' })
+	s.emit(Whitespace)
+	if s.peek(0) == '#' {
+		s.acceptLine()
+		s.emit(Comment)
+	}
+	s.acceptByte('\r')
+	if !s.acceptByte('\n') {
+		s.emit(Whitespace) // a carriage return without a newline, if any
+		return
+	}
+	s.emit(Whitespace)
+
+	// The body is made of the following lines that are blank or more
+	// indented than the key. It ends at the end of its last non-blank line.
+	end := s.pos
+	for i := s.pos; i < len(s.src); {
+		lineEnd := bytes.IndexByte(s.src[i:], '\n')
+		if lineEnd < 0 {
+			lineEnd = len(s.src)
+		} else {
+			lineEnd += i
+		}
+		line := s.src[i:lineEnd]
+		trimmed := bytes.TrimLeft(line, " ")
+		if len(bytes.TrimSpace(line)) > 0 {
+			if len(line)-len(trimmed) <= indent {
+				break
+			}
+			end = lineEnd
+		}
+		i = lineEnd + 1
+	}
+	s.pos = end
+	s.emit(String)
+}